@@ -0,0 +1,73 @@
+package main
+
+import (
+	"AirPort/internal/handlers/board"
+	"AirPort/package/apperrors"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func runImportFlights(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("import-flights")
+	file := fs.String("file", "", "CSV с колонками flightNumber, appointment, departure, seatCapacity или расписание SSIM")
+	format := fs.String("format", "", "формат: csv или ssim, по умолчанию по расширению файла")
+	dryRun := fs.Bool("dry-run", false, "только проверить файл, ничего не добавляя")
+	origin := fs.String("origin", "", "для SSIM: импортировать только вылеты из этого аэропорта (код IATA)")
+	horizon := fs.Int("horizon-days", a.cfg.Schedule.HorizonDays, "для SSIM: на сколько дней вперёд разворачивать периоды")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("не задан -file")
+	}
+	if *format == "" {
+		*format = board.FormatCSV
+		if strings.EqualFold(filepath.Ext(*file), "."+board.FormatSSIM) {
+			*format = board.FormatSSIM
+		}
+	}
+	if *format != board.FormatCSV && *format != board.FormatSSIM {
+		return fmt.Errorf("неизвестный формат: %s", *format)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := board.Parse(f, *format, board.SSIMOptions{Origin: *origin, Now: time.Now(), HorizonDays: *horizon})
+	if err != nil {
+		return err
+	}
+
+	db, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := board.Import(ctx, db, rows, *dryRun)
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && len(appErr.Fields) > 0 {
+		for _, f := range appErr.Fields {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", f.Field, f.Message)
+		}
+		return fmt.Errorf("%s, ошибок: %d", appErr.Message, len(appErr.Fields))
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		fmt.Printf("Проверка пройдена, будет добавлено рейсов: %d\n", len(result.Flights))
+	} else {
+		fmt.Printf("Добавлено рейсов: %d\n", result.Created)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"AirPort/internal/config"
+	"AirPort/package/apperrors"
+	"AirPort/package/database"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// app — общее окружение подкоманд: конфиг сервера и пул соединений с БД
+type app struct {
+	cfg *config.Config
+	db  *pgxpool.Pool
+}
+
+// connect подключается к БД при первом обращении, чтобы -h работал без базы
+func (a *app) connect(ctx context.Context) (*pgxpool.Pool, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+
+	db, err := database.OpenDBClient(ctx, database.Options(a.cfg.Storage))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подключения к БД: %w", err)
+	}
+	a.db = db
+
+	return db, nil
+}
+
+func (a *app) close() {
+	if a.db != nil {
+		a.db.Close()
+	}
+}
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+var commands map[string]command
+
+// Таблица заполняется в init: подкоманды сами обращаются к ней за описанием
+func init() {
+	commands = map[string]command{
+		"migrate":         {"применить миграции (-status — только показать ожидающие)", runMigrate},
+		"create-user":     {"создать пользователя, в том числе сотрудника или главного администратора", runCreateUser},
+		"promote-user":    {"выдать пользователю роль сотрудника или главного администратора", runPromoteUser},
+		"issue-token":     {"выпустить мастер-токен", runIssueToken},
+		"import-flights":  {"загрузить рейсы из CSV или SSIM одной транзакцией", runImportFlights},
+		"import-airports": {"загрузить справочник аэропортов из CSV OurAirports", runImportAirports},
+		"import-airlines": {"загрузить справочник авиакомпаний из CSV", runImportAirlines},
+		"generate-report": {"сформировать отчёт в файл", runGenerateReport},
+		"purge":           {"удалить старые файлы логов и уведомления", runPurge},
+	}
+}
+
+func main() {
+	// Флаги до подкоманды — те же, что у сервера: -config, -db-host и т.д.
+	cfg, args, err := config.LoadArgs("airportctl", os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		usage()
+		return
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	if len(args) == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "неизвестная команда: %s\n\n", args[0])
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a := &app{cfg: cfg}
+	err = cmd.run(ctx, a, args[1:])
+	a.close()
+	if err != nil && !errors.Is(err, flag.ErrHelp) {
+		fail(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Использование: airportctl [флаги конфига] <команда> [флаги команды]")
+	fmt.Fprintln(os.Stderr, "\nКоманды:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, commands[name].usage)
+	}
+
+	fmt.Fprintln(os.Stderr, "\nФлаги команды: airportctl <команда> -h")
+}
+
+// newFlags создаёт набор флагов подкоманды с описанием из таблицы команд
+func newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "airportctl %s — %s\n\n", name, commands[name].usage)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "Ошибка:", describe(err))
+	os.Exit(1)
+}
+
+// describe добавляет к тексту ошибки ошибки полей, которые в HTTP уходят отдельным списком
+func describe(err error) string {
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) || len(appErr.Fields) == 0 {
+		return err.Error()
+	}
+
+	fields := make([]string, len(appErr.Fields))
+	for i, f := range appErr.Fields {
+		fields[i] = f.Field + ": " + f.Message
+	}
+
+	return appErr.Message + " (" + strings.Join(fields, "; ") + ")"
+}
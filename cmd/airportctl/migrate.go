@@ -0,0 +1,46 @@
+package main
+
+import (
+	"AirPort/package/database"
+	"context"
+	"fmt"
+)
+
+func runMigrate(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("migrate")
+	status := fs.Bool("status", false, "только показать ожидающие миграции")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	db, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	if *status {
+		pending, err := database.PendingMigrations(ctx, db)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			fmt.Println("Все миграции применены")
+		}
+		for _, m := range pending {
+			fmt.Println("Ожидает применения:", m.Version)
+		}
+		return nil
+	}
+
+	applied, err := database.Migrate(ctx, db)
+	for _, version := range applied {
+		fmt.Println("Применена миграция", version)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("Новых миграций нет")
+	}
+
+	return nil
+}
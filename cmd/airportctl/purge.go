@@ -0,0 +1,50 @@
+package main
+
+import (
+	"AirPort/internal/handlers/user"
+	"AirPort/package/logs"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+func runPurge(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("purge")
+	days := fs.Int("days", 30, "удалить данные старше указанного числа дней")
+	purgeLogs := fs.Bool("logs", true, "удалить ротированные файлы логов")
+	purgeNotifications := fs.Bool("notifications", true, "удалить уведомления о старых билетах")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *days < 1 {
+		return errors.New("-days должен быть больше нуля")
+	}
+
+	before := time.Now().AddDate(0, 0, -*days)
+
+	if *purgeLogs && a.cfg.Log.File != "" {
+		removed, err := logs.PurgeBackups(a.cfg.Log.File, before)
+		for _, path := range removed {
+			fmt.Println("Удалён файл логов", path)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Удалено файлов логов: %d\n", len(removed))
+	}
+
+	if *purgeNotifications {
+		db, err := a.connect(ctx)
+		if err != nil {
+			return err
+		}
+		removed, err := user.PurgeNotifications(ctx, db, before)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Удалено уведомлений: %d\n", removed)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"AirPort/internal/handlers/reference"
+	"AirPort/package/apperrors"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+func runImportAirports(ctx context.Context, a *app, args []string) error {
+	return loadReference(ctx, a, "import-airports", args,
+		"airports.csv из OurAirports или CSV с колонками iata, icao, name, city, country, timezone, latitude, longitude",
+		reference.ParseAirports, reference.LoadAirports)
+}
+
+func runImportAirlines(ctx context.Context, a *app, args []string) error {
+	return loadReference(ctx, a, "import-airlines", args,
+		"CSV с колонками iata, icao, name, country",
+		reference.ParseAirlines, reference.LoadAirlines)
+}
+
+// loadReference — общая часть загрузки справочников. При -dry-run база не нужна
+func loadReference[T any](
+	ctx context.Context,
+	a *app,
+	name string,
+	args []string,
+	fileUsage string,
+	parse func(io.Reader) (*reference.File[T], error),
+	load func(context.Context, *pgxpool.Pool, *reference.File[T], bool) (*reference.LoadResult, error),
+) error {
+	fs := newFlags(name)
+	path := fs.String("file", "", fileUsage)
+	dryRun := fs.Bool("dry-run", false, "только проверить файл, ничего не загружая")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("не задан -file")
+	}
+
+	f, err := os.Open(*path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer f.Close()
+
+	file, err := parse(f)
+	if err != nil {
+		return err
+	}
+
+	var db *pgxpool.Pool
+	if !*dryRun {
+		if db, err = a.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	result, err := load(ctx, db, file, *dryRun)
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && len(appErr.Fields) > 0 {
+		for _, f := range appErr.Fields {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", f.Field, f.Message)
+		}
+		return fmt.Errorf("%s, ошибок: %d", appErr.Message, len(appErr.Fields))
+	}
+	if err != nil {
+		return err
+	}
+
+	if result.DryRun {
+		fmt.Printf("Проверка пройдена, будет загружено записей: %d, пропущено: %d\n", result.Loaded, result.Skipped)
+	} else {
+		fmt.Printf("Загружено записей: %d, пропущено: %d\n", result.Loaded, result.Skipped)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"AirPort/internal/handlers/report"
+	"AirPort/package/validation"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+func runGenerateReport(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("generate-report")
+	var params report.Report
+	fs.StringVar(&params.Type, "type", "sales", "тип: sales, on_time, load_factor, route_load_factor, cancellations, busiest_hours")
+	fs.StringVar(&params.Format, "format", "pdf", "формат: pdf, csv, xlsx или json")
+	fs.StringVar(&params.From, "from", "", "начало периода, ГГГГ-ММ-ДД")
+	fs.StringVar(&params.To, "to", "", "конец периода, ГГГГ-ММ-ДД")
+	fs.StringVar(&params.Granularity, "granularity", "", "группировка: day, week, month, quarter или year")
+	fs.StringVar(&params.Route, "route", "", "направление")
+	fs.StringVar(&params.Airline, "airline", "", "код авиакомпании")
+	fs.StringVar(&params.FareClass, "fare-class", "", "класс: economy, business или first")
+	fs.StringVar(&params.FlightNumber, "flight", "", "номер рейса")
+	output := fs.String("o", "", "файл для отчёта, по умолчанию report-<дата>.<формат>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := validation.Struct(params); err != nil {
+		return err
+	}
+
+	db, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, a.cfg.Report.JobTimeout)
+	defer cancel()
+
+	file, err := params.GetNewReportData(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	path := *output
+	if path == "" {
+		path = fmt.Sprintf("report-%s.%s", time.Now().Format("20060102-150405"), file.Extension)
+	}
+	if err := os.WriteFile(path, file.Data, 0644); err != nil {
+		return fmt.Errorf("ошибка записи отчёта: %w", err)
+	}
+
+	fmt.Println("Отчёт сохранён в", path)
+	return nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"AirPort/internal/handlers/user"
+	control "AirPort/internal/handlers/userControl"
+	"AirPort/package/auth"
+	"AirPort/package/validation"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var roles = []string{"user", "employee", "master-admin"}
+
+func runCreateUser(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("create-user")
+	var request user.RegisterRequest
+	fs.StringVar(&request.Username, "username", "", "логин")
+	fs.StringVar(&request.Name, "name", "", "имя")
+	fs.StringVar(&request.Email, "email", "", "email")
+	fs.StringVar(&request.Password, "password", "", "пароль; если не задан, читается из стандартного ввода")
+	fs.StringVar(&request.Language, "language", "", "язык сообщений: ru или en")
+	role := fs.String("role", "user", "роль: "+strings.Join(roles, ", "))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if !validRole(*role) {
+		return fmt.Errorf("неизвестная роль: %s", *role)
+	}
+	if request.Password == "" {
+		password, err := readPassword()
+		if err != nil {
+			return err
+		}
+		request.Password = password
+	}
+	if err := validation.Struct(request); err != nil {
+		return err
+	}
+
+	db, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	newUser := request.User()
+	if _, err := newUser.RegisterUser(ctx, db, auth.Options(a.cfg.Auth)); err != nil {
+		return err
+	}
+	if *role != "user" {
+		if err := newUser.Promote(ctx, db, *role == "master-admin"); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Создан пользователь %s (id %d, роль %s)\n", newUser.Username, newUser.Id, *role)
+	return nil
+}
+
+func runPromoteUser(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("promote-user")
+	username := fs.String("username", "", "логин пользователя")
+	masterAdmin := fs.Bool("master-admin", false, "выдать права главного администратора")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" {
+		return errors.New("не задан -username")
+	}
+
+	db, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	target := user.Users{Username: *username}
+	if err := target.Promote(ctx, db, *masterAdmin); err != nil {
+		return err
+	}
+
+	if *masterAdmin {
+		fmt.Printf("Пользователь %s назначен главным администратором\n", *username)
+	} else {
+		fmt.Printf("Пользователь %s назначен сотрудником\n", *username)
+	}
+	fmt.Println("Новая роль попадёт в токен при следующем входе")
+	return nil
+}
+
+func runIssueToken(ctx context.Context, a *app, args []string) error {
+	fs := newFlags("issue-token")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := a.connect(ctx)
+	if err != nil {
+		return err
+	}
+	var token control.Token
+	if err := token.GenerateToken(ctx, db); err != nil {
+		return err
+	}
+
+	fmt.Println(token.Token)
+	return nil
+}
+
+func validRole(role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// readPassword читает пароль из первой строки стандартного ввода, чтобы он не оставался в истории команд
+func readPassword() (string, error) {
+	fmt.Fprint(os.Stderr, "Пароль: ")
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		if err != nil {
+			return "", fmt.Errorf("ошибка чтения пароля: %w", err)
+		}
+		return "", errors.New("пароль не задан")
+	}
+
+	return password, nil
+}
@@ -2,14 +2,31 @@ package main
 
 import (
 	"AirPort/internal/config"
+	"AirPort/internal/handlers"
 	"AirPort/internal/handlers/board"
+	"AirPort/internal/handlers/dashboard"
+	"AirPort/internal/handlers/docs"
+	probes "AirPort/internal/handlers/health"
+	"AirPort/internal/handlers/reference"
 	"AirPort/internal/handlers/report"
 	"AirPort/internal/handlers/tickets"
 	"AirPort/internal/handlers/user"
 	control "AirPort/internal/handlers/userControl"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/cors"
 	"AirPort/package/database"
+	"AirPort/package/delivery"
+	"AirPort/package/health"
+	"AirPort/package/i18n"
+	"AirPort/package/logs"
+	"AirPort/package/metrics"
+	"AirPort/package/requestid"
 	"AirPort/package/server"
+	"AirPort/package/tracing"
 	"context"
+	"errors"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -17,82 +34,151 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 func main() {
-	// Загрузка основного конфига
-	var cfg config.ServerConf
-	if err := cfg.ReadConfig(); err != nil {
-		log.Fatalf("Ошибка чтения основного конфига: %s", err)
+	// Загрузка конфига
+	cfg, err := config.Load(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Ошибка чтения конфига: %s", err)
 	}
 
-	// Загрузка конфига базы данных
-	var dbConf config.StorageConfig
-	if err := dbConf.ReadConfig(); err != nil {
-		log.Fatalf("Ошибка чтения основного конфига: %s", err)
+	// Секции конфига совпадают по полям с параметрами пакетов, поэтому передаются преобразованием типа.
+	// Сами пакеты о конфиге приложения не знают
+
+	// Настройка логирования
+	if err := logs.Init(logs.Options(cfg.Log)); err != nil {
+		log.Fatalf("Ошибка настройки логирования: %s", err)
 	}
-	// Подключение к БД
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	defer logs.Close()
+
+	// Настройка трассировки
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Options(cfg.Tracing))
+	if err != nil {
+		log.Fatalf("Ошибка настройки трассировки: %s", err)
+	}
+	defer shutdownTracing(context.Background())
 
-	pool, err := database.OpenDBClient(ctx, dbConf)
+	// Подключение к БД, ожидание ограничено DB_CONNECT_MAX_WAIT
+	pool, err := database.OpenDBClient(context.Background(), database.Options(cfg.Storage))
 	if err != nil {
 		log.Fatalf("Ошибка подключения к БД: %v", err)
 	}
 	defer pool.Close()
 
-	// Инициализация gin
-	router := gin.Default()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
-	// Middleware для CORS
-	router.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "http://localhost:3000")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type")
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
+	// Применение миграций
+	applied, err := database.Migrate(ctx, pool)
+	if err != nil {
+		log.Fatalf("Ошибка применения миграций: %v", err)
+	}
+	for _, version := range applied {
+		log.Printf("Применена миграция %s", version)
+	}
+
+	// Проверки готовности
+	checker := health.New()
+	checker.AddCheck("database", pool.Ping)
+	checker.AddCheck("migrations", func(ctx context.Context) error {
+		return database.CheckMigrations(ctx, pool)
 	})
 
-	// Инициализация роутов
-	// -- для User
-	userHandler := user.NewHandler(pool)
-	userHandler.RegisterHandler(router)
+	// Метрики пула соединений и рейсов по статусам
+	if err := metrics.RegisterPool(pool); err != nil {
+		log.Fatalf("Ошибка регистрации метрик пула: %v", err)
+	}
+	if err := metrics.RegisterFlightsByStatus(func(ctx context.Context) (map[string]int, error) {
+		var b board.Board
+		return b.CountByStatus(ctx, pool)
+	}); err != nil {
+		log.Fatalf("Ошибка регистрации метрик рейсов: %v", err)
+	}
 
-	// -- для Board
-	boardHandler := board.NewHandler(pool)
-	boardHandler.RegisterHandler(router)
+	// Очередь отчётов
+	reportStore := report.NewStore(cfg.Report.Dir)
+	reportQueue := report.NewQueue(pool, reportStore, cfg.Report.Workers, cfg.Report.QueueSize, cfg.Report.JobTimeout)
+	if err := reportQueue.Start(ctx); err != nil {
+		log.Fatalf("Ошибка запуска очереди отчётов: %v", err)
+	}
+	defer reportQueue.Stop()
+	checker.AddCheck("report_workers", reportQueue.Check)
+
+	// Рассылка отчётов по расписанию
+	reportScheduler := report.NewScheduler(
+		pool,
+		reportStore,
+		delivery.NewMailer(delivery.SMTPOptions(cfg.SMTP)),
+		delivery.NewWebhook(),
+		cfg.Report.ScheduleInterval,
+		cfg.Report.JobTimeout,
+	)
+	reportScheduler.Start()
+	defer reportScheduler.Stop()
+	checker.AddCheck("report_scheduler", reportScheduler.Check)
+
+	// Рейсы на табло по расписаниям регулярных рейсов
+	flightGenerator := board.NewGenerator(pool, cfg.Schedule.HorizonDays, cfg.Schedule.Interval)
+	flightGenerator.Start()
+	defer flightGenerator.Stop()
+	checker.AddCheck("flight_generator", flightGenerator.Check)
 
-	// -- для Control
-	controlHandler := control.NewHandler(pool)
-	controlHandler.RegisterHandler(router)
+	// Инициализация gin
+	router := gin.New()
+	router.Use(
+		requestid.Middleware(),
+		tracing.Middleware(cfg.Tracing.ServiceName),
+		logs.Middleware(),
+		metrics.Middleware(),
+		gin.Recovery(),
+		auth.Middleware(cfg.Auth.JWTSecret, user.Roles(pool)),
+		i18n.Middleware(auth.Language),
+		apperrors.Middleware(),
+	)
+	router.NoRoute(func(c *gin.Context) {
+		c.Error(apperrors.NotFound("маршрут не найден"))
+	})
 
-	// -- для Tickets
-	ticketsHandler := tickets.NewHandler(pool)
-	ticketsHandler.RegisterHandler(router)
+	// Middleware для CORS
+	router.Use(cors.Middleware(cors.Options(cfg.CORS)))
 
-	// -- для Report
-	reportHandler := report.NewHandler(pool)
-	reportHandler.RegisterHandler(router)
+	// Инициализация роутов: /api/v1, устаревшие маршруты без версии и документация
+	registerRoutes(router, cfg, pool, reportQueue, flightGenerator, checker)
 
 	// Запуск сервера
-	server := &server.Server{}
+	serverOptions := server.Options{
+		Port:              cfg.Server.Port,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		TLSCertFile:       cfg.Server.TLSCertFile,
+		TLSKeyFile:        cfg.Server.TLSKeyFile,
+	}
+	server := &server.Server{
+		Health:     checker,
+		DrainDelay: cfg.Server.ShutdownDrain,
+	}
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		if err := server.RunServer(cfg.Port, router); err != nil {
+		if err := server.RunServer(serverOptions, router); err != nil {
 			log.Fatalf("Ошибка при запуске сервера: %s", err)
 		}
 	}()
 
-	log.Printf("\033[32mСервер запущен на: %s:%s\n\033[0m", cfg.Host, cfg.Port)
+	log.Printf("\033[32mСервер запущен на: %s:%s\n\033[0m", cfg.Server.Host, cfg.Server.Port)
 
 	<-done
 
-	ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	ctxShutdown, cancelShutdown := context.WithTimeout(context.Background(), cfg.Server.ShutdownDrain+5*time.Second)
 	defer cancelShutdown()
 
 	if err := server.StopServer(ctxShutdown); err != nil {
@@ -100,3 +186,26 @@ func main() {
 	}
 	log.Println("Сервер успешно остановлен")
 }
+
+// registerRoutes регистрирует обработчики и описывает их маршруты в OpenAPI
+func registerRoutes(engine *gin.Engine, cfg *config.Config, pool *pgxpool.Pool, reportQueue *report.Queue, flightGenerator *board.Generator, checker *health.Checker) *handlers.Router {
+	routes := handlers.NewRouter(engine, cfg.API)
+
+	all := []handlers.Handlers{
+		user.NewHandler(pool, cfg.Auth),
+		board.NewHandler(pool, flightGenerator, cfg.Schedule.HorizonDays),
+		reference.NewHandler(pool),
+		control.NewHandler(pool, cfg.Auth),
+		tickets.NewHandler(pool),
+		report.NewHandler(pool, reportQueue),
+		dashboard.NewHandler(pool, cfg.Dashboard.CacheTTL),
+		probes.NewHandler(checker),
+		docs.NewHandler(routes.Docs),
+	}
+	for _, h := range all {
+		h.RegisterHandler(routes)
+		h.Document(routes)
+	}
+
+	return routes
+}
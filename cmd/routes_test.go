@@ -0,0 +1,35 @@
+package main
+
+import (
+	"AirPort/internal/config"
+	"AirPort/package/health"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestRoutesDocumented не даёт зарегистрировать маршрут без описания в OpenAPI
+// и оставить в документации маршрут, которого больше нет
+func TestRoutesDocumented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, legacy := range []bool{true, false} {
+		engine := gin.New()
+		cfg := &config.Config{API: config.APIConfig{LegacyRoutes: legacy}}
+		routes := registerRoutes(engine, cfg, nil, nil, nil, health.New())
+
+		registered := make(map[string]bool)
+		for _, route := range engine.Routes() {
+			registered[route.Method+" "+route.Path] = true
+			if !routes.Docs.Has(route.Method, route.Path) {
+				t.Errorf("маршрут %s %s не описан в OpenAPI (legacy=%v)", route.Method, route.Path, legacy)
+			}
+		}
+
+		for _, route := range routes.Docs.Routes() {
+			if !registered[route] {
+				t.Errorf("в OpenAPI описан незарегистрированный маршрут %s (legacy=%v)", route, legacy)
+			}
+		}
+	}
+}
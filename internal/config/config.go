@@ -1,40 +1,123 @@
 package config
 
 import (
-	"log"
-
-	"github.com/ilyakaznacheev/cleanenv"
+	"time"
 )
 
+// Config собирает все настройки приложения. Загружается один раз через Load
+// в cmd, где из отдельных секций собираются параметры пакетов.
+// Значения по умолчанию заданы тегом default, а не env-default: cleanenv
+// подставляет env-default в любое нулевое поле, в том числе заданное файлом
+type Config struct {
+	Server    ServerConf      `yaml:"server" toml:"server"`
+	CORS      CORSConfig      `yaml:"cors" toml:"cors"`
+	API       APIConfig       `yaml:"api" toml:"api"`
+	Storage   StorageConfig   `yaml:"storage" toml:"storage"`
+	Auth      AuthConfig      `yaml:"auth" toml:"auth"`
+	Tracing   TracingConfig   `yaml:"tracing" toml:"tracing"`
+	Report    ReportConfig    `yaml:"report" toml:"report"`
+	Dashboard DashboardConfig `yaml:"dashboard" toml:"dashboard"`
+	Schedule  ScheduleConfig  `yaml:"flight_schedule" toml:"flight_schedule"`
+	SMTP      SMTPConfig      `yaml:"smtp" toml:"smtp"`
+	Log       LogConfig       `yaml:"log" toml:"log"`
+}
+
 type ServerConf struct {
-	Port string `env:"PORT" env-default:"8081"`
-	Host string `env:"Host" env-default:"localhost"`
+	Port          string        `yaml:"port" toml:"port" env:"PORT" default:"8081" env-description:"порт HTTP-сервера"`
+	Host          string        `yaml:"host" toml:"host" env:"Host" default:"localhost" env-description:"адрес сервера"`
+	ShutdownDrain time.Duration `yaml:"shutdown_drain" toml:"shutdown_drain" env:"SHUTDOWN_DRAIN" default:"5s" env-description:"пауза перед остановкой, чтобы балансировщик снял трафик"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"HTTP_READ_TIMEOUT" default:"10s" env-description:"время на чтение всего запроса"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" default:"5s" env-description:"время на чтение заголовков запроса"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" default:"10s" env-description:"время на запись ответа"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" default:"60s" env-description:"время жизни простаивающего keep-alive соединения"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" toml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES" default:"1048576" env-description:"максимальный размер заголовков запроса, байт"`
+
+	TLSCertFile string `yaml:"tls_cert_file" toml:"tls_cert_file" env:"TLS_CERT_FILE" env-description:"сертификат TLS, пустое значение включает HTTP"`
+	TLSKeyFile  string `yaml:"tls_key_file" toml:"tls_key_file" env:"TLS_KEY_FILE" env-description:"закрытый ключ TLS"`
 }
 
-func (s *ServerConf) ReadConfig() error {
-	err := cleanenv.ReadConfig("internal/config/.env", s)
-	if err != nil {
-		log.Printf("Ошибка при чтении файла с конфигом: %s", err)
-		return err
-	}
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" default:"http://localhost:3000" env-description:"разрешённые источники через запятую, * разрешает все"`
+	AllowedMethods   []string      `yaml:"allowed_methods" toml:"allowed_methods" env:"CORS_ALLOWED_METHODS" default:"GET,POST,PUT,PATCH,DELETE,OPTIONS" env-description:"разрешённые методы через запятую"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" toml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" default:"Content-Type,Authorization,Accept-Language,X-Request-ID" env-description:"разрешённые заголовки запроса через запятую"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" toml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" default:"X-Request-ID,Location,Content-Disposition,Content-Language,Deprecation,Sunset,Link" env-description:"заголовки ответа, доступные браузеру"`
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" default:"false" env-description:"разрешить cookie и заголовок Authorization между источниками"`
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE" default:"10m" env-description:"время кэширования preflight-ответа"`
+}
 
-	return nil
+type APIConfig struct {
+	LegacyRoutes bool   `yaml:"legacy_routes" toml:"legacy_routes" env:"API_LEGACY_ROUTES" default:"true" env-description:"обслуживать старые маршруты без /api/v1"`
+	LegacySunset string `yaml:"legacy_sunset" toml:"legacy_sunset" env:"API_LEGACY_SUNSET" env-description:"дата отключения старых маршрутов в формате 2006-01-02 для заголовка Sunset"`
 }
 
 type StorageConfig struct {
-	Host     string `env:"DB_HOST" env-default:"localhost"`
-	Port     string `env:"DB_PORT" env-default:"5432"`
-	Database string `env:"DB_DATABASE" env-default:"postgres"`
-	Username string `env:"DB_USERNAME" env-default:"postgres"`
-	Password string `env:"DB_PASSWORD"`
+	Host     string `yaml:"host" toml:"host" env:"DB_HOST" default:"localhost" env-description:"адрес PostgreSQL"`
+	Port     string `yaml:"port" toml:"port" env:"DB_PORT" default:"5432" env-description:"порт PostgreSQL"`
+	Database string `yaml:"database" toml:"database" env:"DB_DATABASE" default:"postgres" env-description:"имя базы данных"`
+	Username string `yaml:"username" toml:"username" env:"DB_USERNAME" default:"postgres" env-description:"пользователь базы данных"`
+	Password string `yaml:"password" toml:"password" env:"DB_PASSWORD" env-description:"пароль базы данных"`
+
+	SSLMode     string `yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE" default:"disable" env-description:"режим TLS: disable, require, verify-ca или verify-full"`
+	SSLRootCert string `yaml:"sslrootcert" toml:"sslrootcert" env:"DB_SSLROOTCERT" env-description:"корневой сертификат для проверки сервера"`
+	SSLCert     string `yaml:"sslcert" toml:"sslcert" env:"DB_SSLCERT" env-description:"клиентский сертификат"`
+	SSLKey      string `yaml:"sslkey" toml:"sslkey" env:"DB_SSLKEY" env-description:"ключ клиентского сертификата"`
+
+	MaxConns          int32         `yaml:"max_conns" toml:"max_conns" env:"DB_MAX_CONNS" default:"10" env-description:"максимальный размер пула соединений"`
+	MinConns          int32         `yaml:"min_conns" toml:"min_conns" env:"DB_MIN_CONNS" default:"0" env-description:"число соединений, которые пул держит открытыми"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"DB_MAX_CONN_LIFETIME" default:"1h" env-description:"время жизни соединения"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"DB_MAX_CONN_IDLE_TIME" default:"30m" env-description:"время простоя, после которого соединение закрывается"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period" env:"DB_HEALTH_CHECK_PERIOD" default:"1m" env-description:"период проверки простаивающих соединений"`
+	StatementTimeout  time.Duration `yaml:"statement_timeout" toml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" default:"30s" env-description:"предельное время выполнения запроса, 0 отключает ограничение"`
+
+	ConnectTimeout time.Duration `yaml:"connect_timeout" toml:"connect_timeout" env:"DB_CONNECT_TIMEOUT" default:"5s" env-description:"время на одну попытку подключения"`
+	ConnectMaxWait time.Duration `yaml:"connect_max_wait" toml:"connect_max_wait" env:"DB_CONNECT_MAX_WAIT" default:"1m" env-description:"сколько ждать базу данных при запуске"`
+}
+
+type AuthConfig struct {
+	JWTSecret string        `yaml:"jwt_secret" toml:"jwt_secret" env:"SECRET_JWT" env-description:"секрет для подписи JWT"`
+	TokenTTL  time.Duration `yaml:"token_ttl" toml:"token_ttl" env:"JWT_TTL" default:"24h" env-description:"время жизни JWT"`
 }
 
-func (c *StorageConfig) ReadConfig() error {
-	err := cleanenv.ReadConfig("internal/config/.env", c)
-	if err != nil {
-		log.Printf("Ошибка при чтении файла с конфигом: %s", err)
-		return err
-	}
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" toml:"exporter" env:"TRACE_EXPORTER" default:"none" env-description:"экспортёр трассировок: none, stdout или otlp"`
+	Endpoint    string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"TRACE_OTLP_ENDPOINT" env-description:"адрес OTLP-коллектора"`
+	Insecure    bool    `yaml:"otlp_insecure" toml:"otlp_insecure" env:"TRACE_OTLP_INSECURE" default:"false" env-description:"подключаться к коллектору без TLS"`
+	ServiceName string  `yaml:"service_name" toml:"service_name" env:"TRACE_SERVICE_NAME" default:"airport" env-description:"имя сервиса в трассировках"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACE_SAMPLE_RATIO" default:"1" env-description:"доля записываемых трассировок от 0 до 1"`
+}
+
+type ReportConfig struct {
+	Dir        string        `yaml:"dir" toml:"dir" env:"REPORTS_DIR" default:"./reports" env-description:"каталог готовых отчётов"`
+	Workers    int           `yaml:"workers" toml:"workers" env:"REPORT_WORKERS" default:"2" env-description:"число обработчиков очереди отчётов"`
+	QueueSize  int           `yaml:"queue_size" toml:"queue_size" env:"REPORT_QUEUE_SIZE" default:"16" env-description:"размер очереди отчётов"`
+	JobTimeout time.Duration `yaml:"job_timeout" toml:"job_timeout" env:"REPORT_JOB_TIMEOUT" default:"5m" env-description:"максимальное время построения отчёта"`
+
+	ScheduleInterval time.Duration `yaml:"schedule_interval" toml:"schedule_interval" env:"REPORT_SCHEDULE_INTERVAL" default:"30s" env-description:"период проверки расписаний отчётов"`
+}
+
+type DashboardConfig struct {
+	CacheTTL time.Duration `yaml:"cache_ttl" toml:"cache_ttl" env:"DASHBOARD_CACHE_TTL" default:"5s" env-description:"время жизни кэша показателей дашборда"`
+}
+
+type ScheduleConfig struct {
+	HorizonDays int           `yaml:"horizon_days" toml:"horizon_days" env:"FLIGHT_SCHEDULE_HORIZON_DAYS" default:"14" env-description:"на сколько дней вперёд расписания заполняют табло"`
+	Interval    time.Duration `yaml:"interval" toml:"interval" env:"FLIGHT_SCHEDULE_INTERVAL" default:"1h" env-description:"период заполнения табло по расписаниям"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"SMTP_HOST" env-description:"адрес SMTP-сервера"`
+	Port     string `yaml:"port" toml:"port" env:"SMTP_PORT" default:"587" env-description:"порт SMTP-сервера"`
+	Username string `yaml:"username" toml:"username" env:"SMTP_USERNAME" env-description:"пользователь SMTP"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD" env-description:"пароль SMTP"`
+	From     string `yaml:"from" toml:"from" env:"SMTP_FROM" default:"reports@airport.local" env-description:"адрес отправителя писем"`
+}
 
-	return nil
+type LogConfig struct {
+	Level      string        `yaml:"level" toml:"level" env:"LOG_LEVEL" default:"info" env-description:"уровень логирования"`
+	Format     string        `yaml:"format" toml:"format" env:"LOG_FORMAT" default:"text" env-description:"формат логов: text или json"`
+	File       string        `yaml:"file" toml:"file" env:"LOG_FILE" default:"./logs.txt" env-description:"файл логов, пустое значение отключает запись в файл"`
+	MaxSizeMB  int           `yaml:"max_size_mb" toml:"max_size_mb" env:"LOG_MAX_SIZE_MB" default:"10" env-description:"размер файла логов до ротации, МБ"`
+	MaxAge     time.Duration `yaml:"max_age" toml:"max_age" env:"LOG_MAX_AGE" default:"24h" env-description:"возраст файла логов до ротации"`
+	MaxBackups int           `yaml:"max_backups" toml:"max_backups" env:"LOG_MAX_BACKUPS" default:"5" env-description:"число хранимых старых файлов логов"`
 }
@@ -0,0 +1,276 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/joho/godotenv"
+)
+
+// legacyFile — прежнее расположение .env, которое подхватывается при запуске из корня репозитория
+const legacyFile = "internal/config/.env"
+
+var (
+	traceExporters = []string{"none", "stdout", "otlp"}
+	logLevels      = []string{"debug", "info", "warn", "error"}
+	logFormats     = []string{"text", "json"}
+	sslModes       = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+)
+
+// Load собирает конфиг слоями: значения по умолчанию, файл из -config или CONFIG_FILE
+// (YAML, TOML, JSON или .env), переменные окружения и флаги командной строки.
+// Каждый следующий слой перекрывает предыдущий
+func Load(name string, args []string) (*Config, error) {
+	cfg, _, err := LoadArgs(name, args)
+	return cfg, err
+}
+
+// LoadArgs работает как Load, но возвращает аргументы после флагов конфига:
+// так утилиты командной строки разбирают свои подкоманды
+func LoadArgs(name string, args []string) (*Config, []string, error) {
+	var cfg Config
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "путь к файлу конфига (.yaml, .toml, .json или .env)")
+	envByFlag := registerFlags(fs, reflect.ValueOf(&cfg).Elem())
+
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	path := *configFile
+	if path == "" {
+		if _, err := os.Stat(legacyFile); err == nil {
+			path = legacyFile
+		}
+	}
+
+	if err := applyDefaults(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return nil, nil, err
+	}
+
+	if path != "" {
+		if err := readFile(path, &cfg); err != nil {
+			return nil, nil, fmt.Errorf("ошибка чтения файла конфига %s: %w", path, err)
+		}
+	}
+
+	// Флаги передаются через окружение, чтобы разбор значений и их приоритет
+	// над файлом оставались за cleanenv
+	var setErr error
+	fs.Visit(func(f *flag.Flag) {
+		if env, ok := envByFlag[f.Name]; ok && setErr == nil {
+			setErr = os.Setenv(env, f.Value.String())
+		}
+	})
+	if setErr != nil {
+		return nil, nil, setErr
+	}
+
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("ошибка чтения конфига: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return &cfg, fs.Args(), nil
+}
+
+// Validate проверяет значения, без которых сервер не сможет корректно работать
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Auth.JWTSecret == "" {
+		errs = append(errs, errors.New("не задан секрет JWT (SECRET_JWT)"))
+	}
+	if c.Auth.TokenTTL <= 0 {
+		errs = append(errs, errors.New("время жизни JWT должно быть больше нуля"))
+	}
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("недопустимый порт сервера: %q", c.Server.Port))
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		errs = append(errs, errors.New("для TLS нужны и сертификат, и ключ"))
+	}
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.MaxHeaderBytes <= 0 {
+		errs = append(errs, errors.New("таймауты и размер заголовков HTTP должны быть больше нуля"))
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("не заданы разрешённые источники CORS"))
+	}
+	if c.API.LegacySunset != "" {
+		if _, err := time.Parse(time.DateOnly, c.API.LegacySunset); err != nil {
+			errs = append(errs, fmt.Errorf("неверная дата отключения старых маршрутов: %q", c.API.LegacySunset))
+		}
+	}
+	if c.Storage.Host == "" || c.Storage.Database == "" {
+		errs = append(errs, errors.New("не заданы адрес или имя базы данных"))
+	}
+	if !oneOf(c.Storage.SSLMode, sslModes) {
+		errs = append(errs, fmt.Errorf("неизвестный режим sslmode: %s", c.Storage.SSLMode))
+	}
+	if c.Storage.MaxConns < 1 || c.Storage.MinConns < 0 || c.Storage.MinConns > c.Storage.MaxConns {
+		errs = append(errs, fmt.Errorf("недопустимый размер пула соединений: %d-%d", c.Storage.MinConns, c.Storage.MaxConns))
+	}
+	if c.Storage.ConnectTimeout <= 0 || c.Storage.StatementTimeout < 0 {
+		errs = append(errs, errors.New("недопустимые таймауты базы данных"))
+	}
+	if !oneOf(c.Tracing.Exporter, traceExporters) {
+		errs = append(errs, fmt.Errorf("неизвестный экспортёр трассировок: %s", c.Tracing.Exporter))
+	}
+	if c.Tracing.Exporter == "otlp" && c.Tracing.Endpoint == "" {
+		errs = append(errs, errors.New("для экспортёра otlp нужен TRACE_OTLP_ENDPOINT"))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("доля трассировок должна быть от 0 до 1: %v", c.Tracing.SampleRatio))
+	}
+	if c.Report.Workers < 1 || c.Report.QueueSize < 1 {
+		errs = append(errs, errors.New("число обработчиков и размер очереди отчётов должны быть больше нуля"))
+	}
+	if c.Report.JobTimeout <= 0 || c.Report.ScheduleInterval <= 0 {
+		errs = append(errs, errors.New("таймауты отчётов должны быть больше нуля"))
+	}
+	if c.Schedule.HorizonDays < 1 || c.Schedule.HorizonDays > 366 {
+		errs = append(errs, fmt.Errorf("горизонт расписаний должен быть от 1 до 366 дней: %d", c.Schedule.HorizonDays))
+	}
+	if c.Schedule.Interval <= 0 {
+		errs = append(errs, errors.New("период заполнения табло по расписаниям должен быть больше нуля"))
+	}
+	if !oneOf(c.Log.Level, logLevels) {
+		errs = append(errs, fmt.Errorf("неизвестный уровень логирования: %s", c.Log.Level))
+	}
+	if !oneOf(c.Log.Format, logFormats) {
+		errs = append(errs, fmt.Errorf("неизвестный формат логов: %s", c.Log.Format))
+	}
+
+	return errors.Join(errs...)
+}
+
+// readFile читает файл конфига. Переменные из .env не перекрывают уже заданное окружение
+func readFile(path string, cfg *Config) error {
+	if strings.ToLower(filepath.Ext(path)) == ".env" {
+		return godotenv.Load(path)
+	}
+
+	return cleanenv.ReadConfig(path, cfg)
+}
+
+// applyDefaults заполняет поля значениями из тега default до чтения файла,
+// поэтому файл может задать и false, и 0, и пустую строку
+func applyDefaults(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyDefaults(v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		def, ok := field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+		if err := setValue(v.Field(i), def); err != nil {
+			return fmt.Errorf("неверное значение по умолчанию для %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func setValue(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("неподдерживаемый тип %s", field.Type())
+		}
+		field.Set(reflect.ValueOf(strings.Split(value, ",")))
+	default:
+		return fmt.Errorf("неподдерживаемый тип %s", field.Type())
+	}
+
+	return nil
+}
+
+// registerFlags заводит флаг на каждое поле с тегом env: DB_HOST превращается в -db-host
+func registerFlags(fs *flag.FlagSet, v reflect.Value) map[string]string {
+	envByFlag := make(map[string]string)
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+
+		if field.Type.Kind() == reflect.Struct {
+			for name, env := range registerFlags(fs, v.Field(i)) {
+				envByFlag[name] = env
+			}
+			continue
+		}
+
+		env, ok := field.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		name := strings.ReplaceAll(strings.ToLower(env), "_", "-")
+		usage := field.Tag.Get("env-description")
+		if def, ok := field.Tag.Lookup("default"); ok {
+			usage = fmt.Sprintf("%s (по умолчанию %s)", usage, def)
+		}
+
+		fs.String(name, "", usage)
+		envByFlag[name] = env
+	}
+
+	return envByFlag
+}
+
+func oneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("не удалось записать конфиг: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SECRET_JWT", "secret")
+
+	path := writeConfig(t, "config.yaml", `
+api:
+  legacy_routes: false
+storage:
+  statement_timeout: 0s
+  max_conns: 20
+tracing:
+  sample_ratio: 0
+log:
+  file: ""
+server:
+  shutdown_drain: 0s
+  idle_timeout: 2m
+`)
+	t.Setenv("HTTP_IDLE_TIMEOUT", "3m")
+	// Флаги передаются через окружение
+	t.Cleanup(func() { os.Unsetenv("DB_MAX_CONNS") })
+
+	cfg, err := Load("test", []string{"-config", path, "-db-max-conns", "30"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"false из файла", cfg.API.LegacyRoutes, false},
+		{"0 из файла", cfg.Storage.StatementTimeout, time.Duration(0)},
+		{"0 из файла для дробного числа", cfg.Tracing.SampleRatio, 0.0},
+		{"пустая строка из файла", cfg.Log.File, ""},
+		{"0 из файла для паузы", cfg.Server.ShutdownDrain, time.Duration(0)},
+		{"окружение важнее файла", cfg.Server.IdleTimeout, 3 * time.Minute},
+		{"флаг важнее файла", cfg.Storage.MaxConns, int32(30)},
+		{"значение по умолчанию", cfg.Storage.MaxConnLifetime, time.Hour},
+		{"список по умолчанию", cfg.CORS.AllowedOrigins, []string{"http://localhost:3000"}},
+		{"строка по умолчанию", cfg.Server.Port, "8081"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("получено %#v, ожидалось %#v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SECRET_JWT", "secret")
+
+	cfg, err := Load("test", nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if !cfg.API.LegacyRoutes || cfg.Storage.StatementTimeout != 30*time.Second || cfg.Log.File != "./logs.txt" || cfg.Auth.TokenTTL != 24*time.Hour {
+		t.Errorf("значения по умолчанию не применены: %+v %+v %+v %+v", cfg.API, cfg.Storage, cfg.Log, cfg.Auth)
+	}
+}
@@ -0,0 +1,109 @@
+package board
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+type routesResponse struct {
+	Routes []string `json:"routes"`
+}
+
+type endPointsResponse struct {
+	EndPoints []string `json:"endPoints"`
+}
+
+type schedulesResponse struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Табло"
+
+	router.DocV1(http.MethodGet, "/flights", openapi.Operation{
+		Tag: tag, Summary: "Табло рейсов",
+		Response: []Board{},
+	})
+	router.DocV1(http.MethodPost, "/flights", openapi.Operation{
+		Tag: tag, Summary: "Добавление рейса",
+		Description: "Пункт назначения — название в appointment или код IATA из справочника аэропортов в destinationCode; " +
+			"код, переданный в appointment, тоже связывается со справочником. Только для сотрудников.",
+		Auth: true, Body: FlightRequest{}, Status: http.StatusCreated, Response: Board{},
+	})
+	router.DocV1(http.MethodPatch, "/flights/:id/status", openapi.Operation{
+		Tag: tag, Summary: "Смена статуса рейса", Description: "Только для сотрудников.", Auth: true,
+		Body: StatusRequest{}, Response: handlers.MessageResponse{},
+	})
+	router.DocV1(http.MethodDelete, "/flights/:id", openapi.Operation{
+		Tag: tag, Summary: "Удаление рейса", Description: "Только для сотрудников.", Auth: true,
+		Status: http.StatusNoContent,
+	})
+	router.DocV1(http.MethodPost, "/flights/import", openapi.Operation{
+		Tag: tag, Summary: "Импорт рейсов из CSV или SSIM",
+		Description: "CSV с заголовком flightNumber, appointment, departure и необязательными seatCapacity, aircraftType, destinationCode " +
+			"или расписание IATA SSIM (записи типа 3): периоды разворачиваются на horizonDays дней вперёд, " +
+			"время вылета переводится из местного времени аэропорта отправления по смещению от UTC из записи. " +
+			"Все рейсы добавляются в одной транзакции; если хоть одна строка с ошибкой, не добавляется ничего, " +
+			"а ошибки возвращаются по строкам: line[5].departure. При dryRun=true рейсы только проверяются. Только для сотрудников.",
+		Auth: true, Query: ImportRequest{}, BodyTypes: []string{"text/csv", "text/x-ssim"},
+		Status: http.StatusCreated, Response: ImportResult{},
+	})
+	router.DocV1(http.MethodGet, "/flight-schedules", openapi.Operation{
+		Tag: tag, Summary: "Расписания регулярных рейсов", Description: "Только для сотрудников.", Auth: true,
+		Response: schedulesResponse{},
+	})
+	router.DocV1(http.MethodPost, "/flight-schedules", openapi.Operation{
+		Tag: tag, Summary: "Создание расписания рейса",
+		Description: "Рейсы по расписанию добавляются на табло на несколько дней вперёд. " +
+			"daysOfWeek — дни выполнения, понедельник — 1, воскресенье — 7; время вылета местное. Только для сотрудников.",
+		Auth: true, Body: Schedule{}, Status: http.StatusCreated, Response: Schedule{},
+	})
+	router.DocV1(http.MethodGet, "/flight-schedules/:id", openapi.Operation{
+		Tag: tag, Summary: "Расписание рейса", Description: "Только для сотрудников.", Auth: true,
+		Response: Schedule{},
+	})
+	router.DocV1(http.MethodPut, "/flight-schedules/:id", openapi.Operation{
+		Tag: tag, Summary: "Изменение расписания рейса",
+		Description: "Будущие рейсы по старому расписанию снимаются с табло и создаются заново. " +
+			"Рейсы, которые меняли или удаляли вручную, и рейсы с билетами остаются как есть. Только для сотрудников.",
+		Auth: true, Body: Schedule{}, Response: Schedule{},
+	})
+	router.DocV1(http.MethodDelete, "/flight-schedules/:id", openapi.Operation{
+		Tag: tag, Summary: "Удаление расписания рейса", Auth: true, Status: http.StatusNoContent,
+		Description: "Будущие рейсы, которые не меняли вручную и на которые нет билетов, снимаются с табло. Только для сотрудников.",
+	})
+	router.DocV1(http.MethodGet, "/routes/origins", openapi.Operation{
+		Tag: tag, Summary: "Пункты отправления",
+		Response: routesResponse{},
+	})
+	router.DocV1(http.MethodGet, "/routes/destinations", openapi.Operation{
+		Tag: tag, Summary: "Пункты назначения из пункта отправления",
+		Query: DestinationsRequest{}, Response: endPointsResponse{},
+	})
+
+	router.Doc(http.MethodGet, "/board/getBoard", openapi.Operation{
+		Tag: tag, Summary: "Табло рейсов",
+		Response: []Board{},
+	})
+	router.Doc(http.MethodPost, "/board/createBoardItem", openapi.Operation{
+		Tag: tag, Summary: "Добавление рейса по логину и паролю сотрудника",
+		Body: CreateFlightRequest{}, Response: handlers.MessageResponse{},
+	})
+	router.Doc(http.MethodPut, "/board/updateBoardStatus", openapi.Operation{
+		Tag: tag, Summary: "Смена статуса рейса по логину и паролю сотрудника",
+		Body: UpdateStatusRequest{}, Response: handlers.MessageResponse{},
+	})
+	router.Doc(http.MethodDelete, "/board/deleteFlight", openapi.Operation{
+		Tag: tag, Summary: "Удаление рейса по логину и паролю сотрудника",
+		Body: DeleteFlightRequest{}, Response: handlers.MessageResponse{},
+	})
+	router.Doc(http.MethodGet, "/board/getAllStartLocations", openapi.Operation{
+		Tag: tag, Summary: "Пункты отправления",
+		Response: routesResponse{},
+	})
+	router.Doc(http.MethodPost, "/board/getAllFinalLocations", openapi.Operation{
+		Tag: tag, Summary: "Пункты назначения из пункта отправления",
+		Body: EndRoutesRequest{}, Response: endPointsResponse{},
+	})
+}
@@ -0,0 +1,101 @@
+package board
+
+import (
+	"AirPort/package/logs"
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+var errGeneratorStopped = errors.New("генератор рейсов по расписаниям не запущен")
+
+// Generator периодически заполняет табло рейсами по расписаниям на horizonDays дней вперёд
+type Generator struct {
+	db          *pgxpool.Pool
+	horizonDays int
+	interval    time.Duration
+
+	running atomic.Bool
+	wake    chan struct{}
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+func NewGenerator(db *pgxpool.Pool, horizonDays int, interval time.Duration) *Generator {
+	return &Generator{
+		db:          db,
+		horizonDays: horizonDays,
+		interval:    interval,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+func (g *Generator) Start() {
+	var ctx context.Context
+	ctx, g.stop = context.WithCancel(context.Background())
+	g.done = make(chan struct{})
+
+	go g.loop(ctx)
+}
+
+func (g *Generator) Stop() {
+	if g.stop == nil {
+		return
+	}
+	g.stop()
+	<-g.done
+}
+
+// Check используется проверкой готовности
+func (g *Generator) Check(ctx context.Context) error {
+	if !g.running.Load() {
+		return errGeneratorStopped
+	}
+
+	return nil
+}
+
+// Notify запускает внеочередной проход после изменения расписаний, не дожидаясь интервала
+func (g *Generator) Notify() {
+	if g == nil {
+		return
+	}
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (g *Generator) loop(ctx context.Context) {
+	defer close(g.done)
+
+	g.running.Store(true)
+	defer g.running.Store(false)
+
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		g.run(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-g.wake:
+		}
+	}
+}
+
+func (g *Generator) run(ctx context.Context) {
+	created, err := Materialise(ctx, g.db, time.Now(), g.horizonDays)
+	if err != nil {
+		logs.NewLog(ctx, "Генератор рейсов по расписаниям", "board", err)
+	}
+	if created > 0 {
+		logs.NewLog(logs.WithAttrs(ctx, "created", created), "Рейсы по расписаниям добавлены на табло", "board", nil)
+	}
+}
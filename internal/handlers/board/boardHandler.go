@@ -2,185 +2,197 @@ package board
 
 import (
 	"AirPort/internal/handlers"
-	"AirPort/internal/handlers/user"
-	"AirPort/package/logs"
-	"fmt"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
+	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+// maxImportSize ограничивает размер файла импорта расписания
+const maxImportSize = 10 << 20
+
 type Handler struct {
-	db *pgxpool.Pool
+	db        *pgxpool.Pool
+	generator *Generator
+	// horizonDays — горизонт импорта SSIM по умолчанию
+	horizonDays int
 }
 
-func NewHandler(db *pgxpool.Pool) handlers.Handlers {
-	return &Handler{db: db}
+func NewHandler(db *pgxpool.Pool, generator *Generator, horizonDays int) handlers.Handlers {
+	return &Handler{db: db, generator: generator, horizonDays: horizonDays}
 }
 
-func (h *Handler) RegisterHandler(router *gin.Engine) {
-	router.GET("/board/getBoard", h.GetBoard)
-	router.POST("board/createBoardItem", h.CreateBoardItem)
-	router.PUT("board/updateBoardStatus", h.UpdateBoardStatus)
-	router.DELETE("board/deleteFlight", h.DeleteFlight)
-	router.GET("/board/getAllStartLocations", h.GetStartRoutes)
-	router.POST("/board/getAllFinalLocations", h.GetEndRoutes)
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.V1.GET("/flights", h.GetBoard)
+	router.V1.POST("/flights", auth.RequireEmployee(), h.CreateFlight)
+	router.V1.PATCH("/flights/:id/status", auth.RequireEmployee(), h.UpdateFlightStatus)
+	router.V1.DELETE("/flights/:id", auth.RequireEmployee(), h.DeleteFlightByID)
+	router.V1.POST("/flights/import", auth.RequireEmployee(), h.ImportFlights)
+	router.V1.GET("/flight-schedules", auth.RequireEmployee(), h.GetSchedules)
+	router.V1.POST("/flight-schedules", auth.RequireEmployee(), h.CreateSchedule)
+	router.V1.GET("/flight-schedules/:id", auth.RequireEmployee(), h.GetSchedule)
+	router.V1.PUT("/flight-schedules/:id", auth.RequireEmployee(), h.UpdateSchedule)
+	router.V1.DELETE("/flight-schedules/:id", auth.RequireEmployee(), h.DeleteSchedule)
+	router.V1.GET("/routes/origins", h.GetStartRoutes)
+	router.V1.GET("/routes/destinations", h.GetDestinations)
+
+	router.Deprecated(http.MethodGet, "/board/getBoard", "/flights", h.GetBoard)
+	router.Deprecated(http.MethodPost, "/board/createBoardItem", "/flights", h.CreateBoardItem)
+	router.Deprecated(http.MethodPut, "/board/updateBoardStatus", "/flights/:id/status", h.UpdateBoardStatus)
+	router.Deprecated(http.MethodDelete, "/board/deleteFlight", "/flights/:id", h.DeleteFlight)
+	router.Deprecated(http.MethodGet, "/board/getAllStartLocations", "/routes/origins", h.GetStartRoutes)
+	router.Deprecated(http.MethodPost, "/board/getAllFinalLocations", "/routes/destinations", h.GetEndRoutes)
 }
 
 func (h *Handler) GetBoard(c *gin.Context) {
 	var boardToGet Board
 
-	board, err := boardToGet.GetBoard(h.db)
+	board, err := database.RetryRead(c.Request.Context(), h.db, boardToGet.GetBoard)
 	if err != nil {
-		if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "внутренняя ошибка сервера"})
+		c.Error(err)
 		return
 	}
 
-	if logErr := logs.NewLog("Доска", "board", nil); logErr != nil {
-		fmt.Printf("Ошибка логирования: %s", logErr)
-	}
 	c.JSON(http.StatusOK, board)
 }
 
 func (h *Handler) CreateBoardItem(c *gin.Context) {
-	type RequestData struct {
-		User  user.Users `json:"user"`
-		Board Board      `json:"board"`
+	var requestData CreateFlightRequest
+
+	if err := handlers.BindJSON(c, &requestData); err != nil {
+		c.Error(err)
+		return
 	}
 
-	var requestData RequestData
+	if err := requestData.User.Check(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&requestData); err != nil {
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	flight := requestData.Board.Board()
+	if err := flight.CreateBoardItem(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	err := requestData.User.CheckAccPassword(h.db)
-	if err != nil {
-		if err.Error() == "неверные данные" {
-			if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-				fmt.Printf("Ошибка логирования: %s", logErr)
-			}
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "в доступе отказано"})
-			return
-		}
-		fmt.Printf("Ошибка при попытке изменить статус рейса: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "Создано")})
+}
+
+// CreateFlight — создание рейса в /api/v1: сотрудник определяется по токену,
+// а не по логину и паролю в теле запроса
+func (h *Handler) CreateFlight(c *gin.Context) {
+	var request FlightRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := requestData.Board.CreateBoardItem(h.db); err != nil {
-		if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при попытке создать рейс: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	flight := request.Board()
+	if err := flight.CreateBoardItem(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Создано"})
+	c.Header("Location", handlers.APIPrefix+"/flights/"+strconv.Itoa(flight.Id))
+	c.JSON(http.StatusCreated, flight)
 }
 
 func (h *Handler) UpdateBoardStatus(c *gin.Context) {
-	type RequestData struct {
-		User  user.Users `json:"user"`
-		Board Board      `json:"board"`
+	var requestData UpdateStatusRequest
+
+	if err := handlers.BindJSON(c, &requestData); err != nil {
+		c.Error(err)
+		return
 	}
 
-	var requestData RequestData
+	if err := requestData.User.Check(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&requestData); err != nil {
-		if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	flight := Board{Id: requestData.Board.Id, Status: requestData.Board.Status}
+	if err := flight.ChangeFlightStatus(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	err := requestData.User.CheckAccPassword(h.db)
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "успешно")})
+}
+
+func (h *Handler) UpdateFlightStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		if err.Error() == "неверные данные" {
-			if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-				fmt.Printf("Ошибка логирования: %s", logErr)
-			}
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "в доступе отказано"})
-			return
-		}
-		fmt.Printf("Ошибка при попытке изменить статус рейса: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+		c.Error(errFlightNotFound)
 		return
 	}
 
-	if err := requestData.Board.ChangeFlightStatus(h.db); err != nil {
-		if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при попытке создать рейс: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	var request StatusRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "успешно"})
+	flight := Board{Id: id, Status: request.Status}
+	if err := flight.ChangeFlightStatus(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "успешно")})
 }
 
 func (h *Handler) DeleteFlight(c *gin.Context) {
-	type RequestData struct {
-		User  user.Users `json:"user"`
-		Board Board      `json:"board"`
+	var requestData DeleteFlightRequest
+
+	if err := handlers.BindJSON(c, &requestData); err != nil {
+		c.Error(err)
+		return
 	}
 
-	var requestData RequestData
+	if err := requestData.User.Check(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
 
-	if err := c.ShouldBindJSON(&requestData); err != nil {
-		if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	flight := Board{Id: requestData.Board.Id}
+	if err := flight.DeleteBoardItem(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	err := requestData.User.CheckAccPassword(h.db)
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "успешно")})
+}
+
+func (h *Handler) DeleteFlightByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
-		if err.Error() == "неверные данные" {
-			if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-				fmt.Printf("Ошибка логирования: %s", logErr)
-			}
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "в доступе отказано"})
-			return
-		}
-		fmt.Printf("Ошибка при попытке удалить рейс: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+		c.Error(errFlightNotFound)
 		return
 	}
 
-	if err := requestData.Board.DeleteBoardItem(h.db); err != nil {
-		if logErr := logs.NewLog("Доска", "board", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при попытке удалить рейс: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	flight := Board{Id: id}
+	if err := flight.DeleteBoardItem(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "успешно"})
+	c.Status(http.StatusNoContent)
 }
 
 func (h *Handler) GetStartRoutes(c *gin.Context) {
 	var board Board
-	routes, err := board.SelectAllFlight(h.db)
+	routes, err := database.RetryRead(c.Request.Context(), h.db, board.SelectAllFlight)
 	if err != nil {
-		if logsErr := logs.NewLog("Board", "board", err); logsErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logsErr)
-		}
-		fmt.Printf("Ошибка при попытке получить список точек отправления: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+		c.Error(err)
 		return
 	}
 
@@ -188,25 +200,182 @@ func (h *Handler) GetStartRoutes(c *gin.Context) {
 }
 
 func (h *Handler) GetEndRoutes(c *gin.Context) {
-	var inputData struct {
-		StartLocation string `json:"startLocation"`
+	var inputData EndRoutesRequest
+
+	if err := handlers.BindJSON(c, &inputData); err != nil {
+		c.Error(err)
+		return
 	}
 
-	if err := c.ShouldBindJSON(&inputData); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "данные не прошли валидацию"})
+	var routesToGet Board
+	rows, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) ([]string, error) {
+		return routesToGet.SelectDepartureEndPoint(ctx, db, inputData.StartLocation)
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"endPoints": rows})
+}
+
+func (h *Handler) GetDestinations(c *gin.Context) {
+	var request DestinationsRequest
+	if err := handlers.BindQuery(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
 	var routesToGet Board
-	rows, err := routesToGet.SelectDepartureEndPoint(h.db, inputData.StartLocation)
+	rows, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) ([]string, error) {
+		return routesToGet.SelectDepartureEndPoint(ctx, db, request.From)
+	})
 	if err != nil {
-		if logsErr := logs.NewLog("Board", "board", err); logsErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logsErr)
-		}
-		fmt.Printf("Ошибка при попытке получить список точек назначения: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"endPoints": rows})
 }
+
+// ImportFlights загружает рейсы из CSV или SSIM, переданного телом запроса.
+// Формат берётся из параметра format, иначе из Content-Type
+func (h *Handler) ImportFlights(c *gin.Context) {
+	var request ImportRequest
+	if err := handlers.BindQuery(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	format := request.Format
+	if format == "" {
+		format = FormatCSV
+		if strings.Contains(c.ContentType(), FormatSSIM) {
+			format = FormatSSIM
+		}
+	}
+
+	horizonDays := request.HorizonDays
+	if horizonDays == 0 {
+		horizonDays = h.horizonDays
+	}
+
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	rows, err := Parse(body, format, SSIMOptions{Origin: request.Origin, Now: time.Now(), HorizonDays: horizonDays})
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = apperrors.BadRequest("файл импорта больше %d МБ", maxImportSize>>20)
+	}
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := Import(c.Request.Context(), h.db, rows, request.DryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status := http.StatusCreated
+	if request.DryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, result)
+}
+
+func (h *Handler) GetSchedules(c *gin.Context) {
+	var schedule Schedule
+
+	schedules, err := database.RetryRead(c.Request.Context(), h.db, schedule.GetAll)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+func (h *Handler) GetSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errScheduleNotFound)
+		return
+	}
+
+	request := Schedule{Id: id}
+	schedule, err := database.RetryRead(c.Request.Context(), h.db, request.Get)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// CreateSchedule сохраняет расписание, рейсы по нему появляются на табло после прохода генератора
+func (h *Handler) CreateSchedule(c *gin.Context) {
+	var schedule Schedule
+	if err := handlers.BindJSON(c, &schedule); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := schedule.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := schedule.Create(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+	h.generator.Notify()
+
+	c.Header("Location", handlers.APIPrefix+"/flight-schedules/"+strconv.Itoa(schedule.Id))
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *Handler) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errScheduleNotFound)
+		return
+	}
+
+	var schedule Schedule
+	if err := handlers.BindJSON(c, &schedule); err != nil {
+		c.Error(err)
+		return
+	}
+	schedule.Id = id
+
+	if err := schedule.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := schedule.Update(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+	h.generator.Notify()
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *Handler) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errScheduleNotFound)
+		return
+	}
+
+	schedule := Schedule{Id: id}
+	if err := schedule.Delete(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,331 @@
+package board
+
+import (
+	"AirPort/package/apperrors"
+	"AirPort/package/csvtable"
+	"AirPort/package/validation"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatSSIM = "ssim"
+
+	// MaxImportRows ограничивает размер одной транзакции импорта
+	MaxImportRows = 5000
+
+	// ssimMinLength — длина записи SSIM до кода типа ВС включительно
+	ssimMinLength = 75
+	ssimDate      = "02Jan06"
+	ssimOpenDate  = "00XXX00"
+)
+
+// ssimConfiguration — конфигурация салона вида Y180 или C12Y150
+var ssimConfiguration = regexp.MustCompile(`[A-Z](\d+)`)
+
+// ImportRow — рейс из файла импорта и место в файле, на которое указывают ошибки
+type ImportRow struct {
+	Line   int
+	Flight FlightRequest
+
+	// date отличает рейсы, развёрнутые из одной записи SSIM
+	date string
+	err  error
+}
+
+type ImportResult struct {
+	DryRun  bool    `json:"dryRun"`
+	Total   int     `json:"total"`
+	Created int     `json:"created"`
+	Flights []Board `json:"flights"`
+}
+
+// SSIMOptions задаёт, какие участки расписания SSIM попадут на табло
+type SSIMOptions struct {
+	// Origin — код аэропорта, вылеты из которого импортируются; пустой — все участки
+	Origin string
+	// Now — с этого момента разворачиваются периоды; уже прошедшие вылеты пропускаются
+	Now time.Time
+	// HorizonDays — на сколько дней вперёд от Now разворачиваются периоды
+	HorizonDays int
+}
+
+// Parse читает файл импорта в формате csv или ssim
+func Parse(r io.Reader, format string, opts SSIMOptions) ([]ImportRow, error) {
+	if format == FormatSSIM {
+		return ParseSSIM(r, opts)
+	}
+
+	return ParseCSV(r)
+}
+
+// ParseCSV читает рейсы из CSV с заголовком, порядок колонок любой.
+// Колонки seatCapacity, aircraftType и destinationCode необязательны
+func ParseCSV(r io.Reader) ([]ImportRow, error) {
+	// Пункт назначения — название в appointment или код аэропорта в destinationCode
+	t, err := csvtable.New(r, []string{"flightNumber"}, []string{"departure"}, []string{"appointment", "destinationCode"})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ImportRow
+	for {
+		line, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row := ImportRow{Line: line, Flight: FlightRequest{
+			FlightNumber:    t.Value("flightNumber"),
+			Appointment:     t.Value("appointment"),
+			Departure:       t.Value("departure"),
+			AircraftType:    t.Value("aircraftType"),
+			DestinationCode: t.Value("destinationCode"),
+		}}
+		if capacity := t.Value("seatCapacity"); capacity != "" {
+			n, err := strconv.Atoi(capacity)
+			if err != nil {
+				row.err = apperrors.Field("seatCapacity", "должно быть целым числом")
+			}
+			row.Flight.SeatCapacity = n
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseSSIM читает записи типа 3 (участки рейсов) из расписания в формате IATA SSIM
+// и разворачивает период выполнения в рейсы по датам на opts.HorizonDays дней вперёд.
+// Пунктом назначения на табло становится код аэропорта прилёта. Время отправления
+// в SSIM местное для аэропорта вылета, поэтому оно переводится по смещению от UTC из записи
+func ParseSSIM(r io.Reader, opts SSIMOptions) ([]ImportRow, error) {
+	if opts.HorizonDays < 1 {
+		return nil, apperrors.BadRequest("не задан горизонт импорта SSIM")
+	}
+	year, month, day := opts.Now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, opts.HorizonDays)
+	origin := strings.ToUpper(opts.Origin)
+
+	scanner := bufio.NewScanner(r)
+	var rows []ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		record := scanner.Text()
+		if !strings.HasPrefix(record, "3") {
+			continue
+		}
+		if len(record) < ssimMinLength {
+			rows = append(rows, ImportRow{Line: line, err: apperrors.BadRequest("запись SSIM короче %d символов", ssimMinLength)})
+			continue
+		}
+
+		if origin != "" && strings.TrimSpace(record[36:39]) != origin {
+			continue
+		}
+
+		leg, err := parseSSIMLeg(record)
+		if err != nil {
+			rows = append(rows, ImportRow{Line: line, Flight: leg.flight, err: err})
+			continue
+		}
+
+		first, last := leg.from, leg.to
+		if first.Before(today) {
+			first = today
+		}
+		if last.IsZero() || last.After(horizon) {
+			last = horizon
+		}
+
+		for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+			// В SSIM понедельник — 1, воскресенье — 7
+			weekday := (int(day.Weekday())+6)%7 + 1
+			if !leg.days[weekday] {
+				continue
+			}
+
+			// Дата периода — дата вылета по местному времени аэропорта отправления
+			departure := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, leg.location).Add(leg.departure)
+			if !departure.After(opts.Now) {
+				continue
+			}
+
+			flight := leg.flight
+			flight.Departure = departure.In(time.Local).Format(TimeFormat)
+			rows = append(rows, ImportRow{Line: line, Flight: flight, date: day.Format(time.DateOnly)})
+		}
+		if len(rows) > MaxImportRows {
+			return nil, apperrors.BadRequest("расписание SSIM даёт больше %d рейсов, уменьшите горизонт импорта", MaxImportRows)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, apperrors.BadRequest("ошибка чтения файла SSIM").Wrap(err)
+	}
+
+	return rows, nil
+}
+
+type ssimLeg struct {
+	flight    FlightRequest
+	from, to  time.Time
+	days      [8]bool
+	departure time.Duration
+	// location — смещение местного времени аэропорта вылета от UTC
+	location *time.Location
+}
+
+// parseSSIMLeg разбирает поля записи типа 3 по позициям из главы 7 SSIM
+func parseSSIMLeg(record string) (ssimLeg, error) {
+	var leg ssimLeg
+
+	airline := strings.TrimSpace(record[2:5])
+	number := strings.TrimLeft(strings.TrimSpace(record[5:9]), "0")
+	leg.flight = FlightRequest{
+		FlightNumber: strings.ToUpper(airline + number),
+		Appointment:  strings.TrimSpace(record[54:57]),
+		AircraftType: strings.TrimSpace(record[72:75]),
+	}
+
+	if len(record) >= 192 {
+		seats := 0
+		for _, match := range ssimConfiguration.FindAllStringSubmatch(record[172:192], -1) {
+			n, _ := strconv.Atoi(match[1])
+			seats += n
+		}
+		leg.flight.SeatCapacity = seats
+	}
+
+	var err error
+	if leg.from, err = time.Parse(ssimDate, record[14:21]); err != nil {
+		return leg, apperrors.BadRequest("неверная дата начала периода: %s", record[14:21])
+	}
+	if to := record[21:28]; to != ssimOpenDate {
+		if leg.to, err = time.Parse(ssimDate, to); err != nil {
+			return leg, apperrors.BadRequest("неверная дата окончания периода: %s", to)
+		}
+	}
+
+	for _, c := range record[28:35] {
+		if c >= '1' && c <= '7' {
+			leg.days[c-'0'] = true
+		}
+	}
+
+	departure, err := time.Parse("1504", record[39:43])
+	if err != nil {
+		return leg, apperrors.BadRequest("неверное время отправления: %s", record[39:43])
+	}
+	leg.departure = time.Duration(departure.Hour())*time.Hour + time.Duration(departure.Minute())*time.Minute
+
+	if leg.location, err = ssimOffset(record[47:52]); err != nil {
+		return leg, err
+	}
+
+	return leg, nil
+}
+
+// ssimOffset разбирает смещение местного времени от UTC вида +0300 или -0530
+func ssimOffset(value string) (*time.Location, error) {
+	offset, err := time.Parse("-0700", value)
+	if err != nil {
+		return nil, apperrors.BadRequest("неверное смещение от UTC аэропорта вылета: %s", value)
+	}
+	_, seconds := offset.Zone()
+
+	return time.FixedZone(value, seconds), nil
+}
+
+// Import проверяет рейсы по тем же правилам, что и создание рейса через API, и добавляет
+// их в одной транзакции. Если хоть одна строка не прошла проверку, не добавляется ничего,
+// а ошибки возвращаются по строкам файла. При dryRun транзакция откатывается
+func Import(ctx context.Context, db *pgxpool.Pool, rows []ImportRow, dryRun bool) (*ImportResult, error) {
+	if len(rows) == 0 {
+		return nil, apperrors.BadRequest("в файле нет рейсов для импорта")
+	}
+	if len(rows) > MaxImportRows {
+		return nil, apperrors.BadRequest("в файле больше %d рейсов, разделите его на части", MaxImportRows)
+	}
+
+	var fields []apperrors.FieldError
+	valid := make([]ImportRow, 0, len(rows))
+	for _, row := range rows {
+		err := row.err
+		if err == nil {
+			err = validation.Struct(row.Flight)
+		}
+		if err != nil {
+			fields = append(fields, row.errors(err)...)
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при начале транзакции импорта: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result := &ImportResult{DryRun: dryRun, Total: len(rows)}
+	for _, row := range valid {
+		flight := row.Flight.Board()
+		if err := flight.insert(ctx, tx); err != nil {
+			// Ошибка БД прерывает транзакцию, дальше проверять строки бессмысленно
+			var appErr *apperrors.Error
+			if !errors.As(err, &appErr) {
+				return nil, err
+			}
+			fields = append(fields, row.errors(err)...)
+			continue
+		}
+		result.Flights = append(result.Flights, flight)
+	}
+
+	if len(fields) > 0 {
+		return nil, apperrors.Validation("файл импорта содержит ошибки, рейсы не добавлены").WithFields(fields...)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка при фиксации импорта: %w", err)
+	}
+	result.Created = len(result.Flights)
+
+	return result, nil
+}
+
+// errors привязывает ошибки строки к её месту в файле: line[5].departure
+func (r ImportRow) errors(err error) []apperrors.FieldError {
+	prefix := fmt.Sprintf("line[%d]", r.Line)
+	if r.date != "" {
+		prefix += "[" + r.date + "]"
+	}
+
+	appErr := apperrors.From(err)
+	if len(appErr.Fields) == 0 {
+		return []apperrors.FieldError{appErr.AsField(prefix)}
+	}
+
+	return appErr.WithFieldPrefix(prefix).Fields
+}
+
+// String выводит рейс в строке отчёта об импорте
+func (r ImportRow) String() string {
+	return fmt.Sprintf("строка %d: %s %s %s", r.Line, r.Flight.FlightNumber, r.Flight.Appointment, r.Flight.Departure)
+}
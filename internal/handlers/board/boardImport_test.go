@@ -0,0 +1,317 @@
+package board
+
+import (
+	"AirPort/package/apperrors"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ssimRecord собирает запись типа 3 из полей по позициям SSIM; остальное заполняется пробелами
+func ssimRecord(fields map[int]string) string {
+	record := []byte(strings.Repeat(" ", 200))
+	record[0] = '3'
+	for pos, value := range fields {
+		copy(record[pos:], value)
+	}
+
+	return string(record)
+}
+
+// ssimLegRecord — участок SU100 Шереметьево — Пулково с 09:00 по московскому времени
+func ssimLegRecord(overrides map[int]string) string {
+	fields := map[int]string{
+		2:   "SU ",
+		5:   "0100",
+		9:   "01",
+		11:  "01",
+		13:  "J",
+		14:  "01OCT26",
+		21:  "00XXX00",
+		28:  "1234567",
+		36:  "SVO",
+		39:  "0900",
+		43:  "0900",
+		47:  "+0300",
+		54:  "LED",
+		72:  "320",
+		172: "C12Y150",
+	}
+	for pos, value := range overrides {
+		fields[pos] = value
+	}
+
+	return ssimRecord(fields)
+}
+
+func TestParseSSIM(t *testing.T) {
+	msk := time.FixedZone("+0300", 3*60*60)
+	// Понедельник, 15:00 по Москве: сегодняшний рейс в 09:00 уже улетел
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	departure := func(day int) string {
+		return time.Date(2026, 10, day, 9, 0, 0, 0, msk).In(time.Local).Format(TimeFormat)
+	}
+
+	tests := []struct {
+		name    string
+		records []string
+		opts    SSIMOptions
+		want    []string
+		err     bool
+	}{
+		{
+			name:    "открытый период разворачивается до горизонта",
+			records: []string{ssimLegRecord(nil)},
+			opts:    SSIMOptions{Now: now, HorizonDays: 3},
+			want:    []string{departure(20), departure(21), departure(22)},
+		},
+		{
+			name:    "период с датой окончания",
+			records: []string{ssimLegRecord(map[int]string{21: "21OCT26"})},
+			opts:    SSIMOptions{Now: now, HorizonDays: 14},
+			want:    []string{departure(20), departure(21)},
+		},
+		{
+			name:    "дни выполнения: понедельник — 1, воскресенье — 7",
+			records: []string{ssimLegRecord(map[int]string{28: " 2 4  7"})},
+			opts:    SSIMOptions{Now: now, HorizonDays: 7},
+			want:    []string{departure(20), departure(22), departure(25)},
+		},
+		{
+			name:    "период ещё не начался",
+			records: []string{ssimLegRecord(map[int]string{14: "24OCT26", 28: "     67"})},
+			opts:    SSIMOptions{Now: now, HorizonDays: 12},
+			want:    []string{departure(24), departure(25), departure(31)},
+		},
+		{
+			name: "только вылеты из origin",
+			records: []string{
+				ssimLegRecord(map[int]string{36: "LED", 54: "SVO"}),
+				ssimLegRecord(map[int]string{28: "  3    "}),
+			},
+			opts: SSIMOptions{Origin: "svo", Now: now, HorizonDays: 7},
+			want: []string{departure(21)},
+		},
+		{
+			name: "записи других типов пропускаются",
+			records: []string{
+				"1AIRLINE STANDARD SCHEDULE DATA SET",
+				"2LSU  0008S26",
+				ssimLegRecord(map[int]string{28: "  3    "}),
+				"5 SU",
+			},
+			opts: SSIMOptions{Now: now, HorizonDays: 7},
+			want: []string{departure(21)},
+		},
+		{
+			name:    "без горизонта",
+			records: []string{ssimLegRecord(nil)},
+			opts:    SSIMOptions{Now: now},
+			err:     true,
+		},
+		{
+			name:    "больше рейсов, чем допускает импорт",
+			records: []string{ssimLegRecord(nil), ssimLegRecord(map[int]string{5: "0200"})},
+			opts:    SSIMOptions{Now: now, HorizonDays: MaxImportRows/2 + 1},
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseSSIM(strings.NewReader(strings.Join(tt.records, "\n")), tt.opts)
+			if tt.err {
+				if !errors.Is(err, apperrors.ErrBadRequest) {
+					t.Fatalf("ожидалась ошибка запроса, получено %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			var got []string
+			for _, row := range rows {
+				if row.err != nil {
+					t.Fatalf("строка %d: %v", row.Line, row.err)
+				}
+				got = append(got, row.Flight.Departure)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("вылеты %v, ожидались %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSIMColumns(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	opts := SSIMOptions{Now: now, HorizonDays: 1}
+
+	tests := []struct {
+		name   string
+		record string
+		want   FlightRequest
+	}{
+		{
+			name:   "номер без ведущих нулей, прилёт, тип ВС и места по всем классам",
+			record: ssimLegRecord(nil),
+			want:   FlightRequest{FlightNumber: "SU100", Appointment: "LED", AircraftType: "320", SeatCapacity: 162},
+		},
+		{
+			name:   "код авиакомпании из двух символов с цифрой",
+			record: ssimLegRecord(map[int]string{2: "U6 ", 5: "0007", 72: "73H", 172: "Y189"}),
+			want:   FlightRequest{FlightNumber: "U67", Appointment: "LED", AircraftType: "73H", SeatCapacity: 189},
+		},
+		{
+			name:   "запись без конфигурации салона",
+			record: ssimLegRecord(nil)[:ssimMinLength],
+			want:   FlightRequest{FlightNumber: "SU100", Appointment: "LED", AircraftType: "320"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseSSIM(strings.NewReader(tt.record), opts)
+			if err != nil || len(rows) != 1 {
+				t.Fatalf("ожидался один рейс, получено %d, ошибка %v", len(rows), err)
+			}
+
+			got := rows[0].Flight
+			got.Departure = ""
+			if got != tt.want {
+				t.Errorf("рейс %+v, ожидался %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSSIMRecordErrors(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		record string
+	}{
+		{"короткая запись", ssimLegRecord(nil)[:60]},
+		{"неверная дата начала", ssimLegRecord(map[int]string{14: "32OCT26"})},
+		{"неверная дата окончания", ssimLegRecord(map[int]string{21: "31XYZ26"})},
+		{"неверное время отправления", ssimLegRecord(map[int]string{39: "2560"})},
+		{"нет смещения от UTC", ssimLegRecord(map[int]string{47: "     "})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseSSIM(strings.NewReader("\n"+tt.record), SSIMOptions{Now: now, HorizonDays: 7})
+			if err != nil {
+				t.Fatalf("ошибка записи не должна прерывать разбор файла: %v", err)
+			}
+			if len(rows) != 1 || rows[0].err == nil {
+				t.Fatalf("ожидалась одна строка с ошибкой, получено %+v", rows)
+			}
+			if rows[0].Line != 2 {
+				t.Errorf("ошибка в строке %d, ожидалась 2", rows[0].Line)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []ImportRow
+	}{
+		{
+			name: "обязательные колонки",
+			data: "flightNumber,appointment,departure\nSU100,Санкт-Петербург,2099-01-01 09:00:00\n",
+			want: []ImportRow{{Line: 2, Flight: FlightRequest{FlightNumber: "SU100", Appointment: "Санкт-Петербург", Departure: "2099-01-01 09:00:00"}}},
+		},
+		{
+			name: "BOM, регистр и порядок колонок",
+			data: "\ufeffDEPARTURE, SeatCapacity ,FlightNumber,aircrafttype,destinationCode\n2099-01-01 09:00:00,180,SU100,320,LED\n",
+			want: []ImportRow{{Line: 2, Flight: FlightRequest{
+				FlightNumber: "SU100", Departure: "2099-01-01 09:00:00", SeatCapacity: 180, AircraftType: "320", DestinationCode: "LED",
+			}}},
+		},
+		{
+			name: "короткая строка и пробелы вокруг значений",
+			data: "flightNumber,departure,appointment\n SU100 , 2099-01-01 09:00:00\n",
+			want: []ImportRow{{Line: 2, Flight: FlightRequest{FlightNumber: "SU100", Departure: "2099-01-01 09:00:00"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseCSV(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.want) {
+				t.Errorf("строки %+v, ожидались %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"пустой файл", ""},
+		{"разделитель точка с запятой", "flightNumber;appointment;departure\n"},
+		{"нет номера рейса", "appointment,departure\n"},
+		{"нет пункта назначения", "flightNumber,departure\n"},
+		{"незакрытая кавычка", "flightNumber,appointment,departure\nSU100,\"Москва,2099-01-01 09:00:00\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseCSV(strings.NewReader(tt.data)); !errors.Is(err, apperrors.ErrBadRequest) {
+				t.Errorf("ожидалась ошибка запроса, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestParseCSVMalformedRow(t *testing.T) {
+	tests := []struct {
+		name string
+		row  string
+		line int
+	}{
+		{"незакрытая кавычка в начале поля", "\"abc,2099", 3},
+		{"кавычка внутри поля", "a\"b,c,d", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := "flightNumber,appointment,departure\nSU100,Сочи,2099-01-01 09:00:00\n" + tt.row + "\n"
+			_, err := ParseCSV(strings.NewReader(data))
+
+			var appErr *apperrors.Error
+			if !errors.As(err, &appErr) || !errors.Is(err, apperrors.ErrBadRequest) {
+				t.Fatalf("ожидалась ошибка запроса, получено %v", err)
+			}
+			if want := fmt.Sprintf("строке %d", tt.line); !strings.Contains(appErr.Error(), want) {
+				t.Errorf("ошибка %q не указывает на строку %d", appErr.Error(), tt.line)
+			}
+		})
+	}
+}
+
+func TestParseCSVSeatCapacity(t *testing.T) {
+	rows, err := ParseCSV(strings.NewReader("flightNumber,appointment,departure,seatCapacity\nSU100,Сочи,2099-01-01 09:00:00,много\n"))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	fields := rows[0].errors(rows[0].err)
+	if len(fields) != 1 || fields[0].Field != "line[2].seatCapacity" {
+		t.Errorf("ожидалась ошибка line[2].seatCapacity, получено %+v", fields)
+	}
+}
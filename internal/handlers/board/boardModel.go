@@ -1,18 +1,37 @@
 package board
 
 import (
+	"AirPort/internal/handlers/reference"
+	"AirPort/package/apperrors"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
+	"AirPort/package/validation"
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
 const (
 	DefaultStatus  = "Регистрация"
 	StatusCanceled = "Отменён"
+	StatusDeparted = "Вылетел"
 	TimeFormat     = "2006-01-02 15:04:05"
+
+	// DefaultSeatCapacity соответствует схеме мест A00-C20, по которой выдаются билеты
+	DefaultSeatCapacity = 63
+)
+
+var (
+	errFlightNotFound = apperrors.NotFound("рейс не найден")
+	errFlightExists   = apperrors.Conflict("такой рейс уже существует")
+
+	iataCode = regexp.MustCompile(validation.Patterns["iata"])
 )
 
 type Board struct {
@@ -21,78 +40,134 @@ type Board struct {
 	Appointment  string `db:"appointment" json:"appointment"`
 	Departure    string `db:"departure" json:"departure"`
 	Status       string `db:"status" json:"status"`
+	// StatusText — статус на языке запроса, Status остаётся значением из базы
+	StatusText   string `db:"-" json:"statusText,omitempty"`
+	SeatCapacity int    `db:"seat_capacity" json:"seatCapacity,omitempty"`
+	AircraftType string `db:"aircraft_type" json:"aircraftType,omitempty"`
+	// DestinationCode — код IATA аэропорта назначения из справочника
+	DestinationCode string `db:"destination_code" json:"destinationCode,omitempty"`
+	// AirlineName — название авиакомпании из справочника по первым двум символам номера рейса
+	AirlineName string `db:"-" json:"airlineName,omitempty"`
 }
 
-func (b *Board) CreateBoardItem(db *pgxpool.Pool) error {
-	if len(b.FlightNumber) != 6 {
-		return fmt.Errorf("недопустимый номер рейса")
-	}
+func (b *Board) CreateBoardItem(ctx context.Context, db *pgxpool.Pool) error {
+	return b.insert(ctx, db)
+}
 
+// insert добавляет рейс на табло; рейс с тем же номером в ту же дату — Conflict
+func (b *Board) insert(ctx context.Context, db database.Querier) error {
 	departureTime, err := time.Parse(TimeFormat, b.Departure)
 	if err != nil {
-		return fmt.Errorf("неверный формат времени отправления: %w", err)
+		return apperrors.Field("departure", "неверный формат, ожидается %s", TimeFormat).Wrap(err)
 	}
-
-	ctx := context.Background()
-
-	checkQuery := `
-		SELECT COUNT(*) 
-		FROM Board 
-		WHERE flightNumber = $1
-	`
-
-	var flightNumberCount int
-	if err := db.QueryRow(ctx, checkQuery, b.FlightNumber).Scan(&flightNumberCount); err != nil {
-		return fmt.Errorf("ошибка при проверке рейса: %w", err)
+	if err := b.resolveDestination(ctx, db); err != nil {
+		return err
 	}
-	if flightNumberCount != 0 {
-		return fmt.Errorf("такой рейс уже существует")
+
+	if b.SeatCapacity <= 0 {
+		b.SeatCapacity = DefaultSeatCapacity
 	}
 
+	// Регулярный рейс летает под одним номером каждый день, поэтому номер уникален в пределах даты.
+	// ON CONFLICT не прерывает транзакцию импорта, и следующие строки продолжают проверяться
 	query := `
-		INSERT INTO Board (flightNumber, appointment, departure, status, status_change_time)
-		VALUES ($1, $2, $3, 'Регистрация', NOW())
+		INSERT INTO Board (flightNumber, appointment, departure, status, status_change_time, seat_capacity, aircraft_type, destination_code)
+		VALUES ($1, $2, $3, $4, NOW(), $5, $6, NULLIF($7::text, ''))
+		ON CONFLICT (flightNumber, (departure::date)) DO NOTHING
+		RETURNING id
 	`
 
-	if _, err := db.Exec(
+	err = db.QueryRow(
 		ctx,
 		query,
 		b.FlightNumber,
 		b.Appointment,
 		departureTime,
-	); err != nil {
+		DefaultStatus,
+		b.SeatCapacity,
+		b.AircraftType,
+		b.DestinationCode,
+	).Scan(&b.Id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errFlightExists
+	}
+	if database.IsUniqueViolation(err) {
+		return errFlightExists.Wrap(err)
+	}
+	if err != nil {
 		return fmt.Errorf("ошибка при добавлении рейса: %w", err)
 	}
+	b.Status = DefaultStatus
 
 	return nil
 }
 
-func (b *Board) DeleteBoardItem(db *pgxpool.Pool) error {
-	ctx := context.Background()
+// resolveDestination связывает рейс с аэропортом из справочника. Код передаётся в destinationCode
+// или вместо названия в appointment, тогда названием становится город аэропорта.
+// Неизвестный код в appointment остаётся обычным текстом, как до появления справочника
+func (b *Board) resolveDestination(ctx context.Context, db database.Querier) error {
+	if b.DestinationCode == "" && b.Appointment == "" {
+		return apperrors.Field("appointment", "обязательное поле")
+	}
+
+	code := b.DestinationCode
+	if code == "" && iataCode.MatchString(b.Appointment) {
+		code = b.Appointment
+	}
+	if code == "" {
+		return nil
+	}
+
+	airport, err := reference.FindAirport(ctx, db, code)
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound) && b.DestinationCode == "":
+		return nil
+	case errors.Is(err, apperrors.ErrNotFound):
+		return apperrors.Field("destinationCode", "аэропорт %s не найден в справочнике", b.DestinationCode)
+	case err != nil:
+		return err
+	}
+
+	b.DestinationCode = airport.Iata
+	if b.Appointment == "" || strings.EqualFold(b.Appointment, airport.Iata) {
+		b.Appointment = airport.City
+		if b.Appointment == "" {
+			b.Appointment = airport.Name
+		}
+	}
 
+	return nil
+}
+
+func (b *Board) DeleteBoardItem(ctx context.Context, db *pgxpool.Pool) error {
 	query := `
 		DELETE FROM Board
 		WHERE id = $1
 	`
-	_, err := db.Exec(ctx, query, b.Id)
+	result, err := db.Exec(ctx, query, b.Id)
 	if err != nil {
-		return fmt.Errorf("ошибка при попытке удалить из бд: %s", err)
+		return fmt.Errorf("ошибка при попытке удалить из бд: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errFlightNotFound
 	}
 
 	return nil
 }
 
-func (b *Board) GetBoard(db *pgxpool.Pool) ([]Board, error) {
-	ctx := context.Background()
-
+func (b *Board) GetBoard(ctx context.Context, db *pgxpool.Pool) ([]Board, error) {
 	query := `
 		SELECT 
-			id, 
-			flightnumber, 
-			appointment, 
-			TO_CHAR(departure, 'YYYY-MM-DD HH24:MI:SS'), 
-			status 
-		FROM Board
+			b.id, 
+			b.flightnumber, 
+			b.appointment, 
+			TO_CHAR(b.departure, 'YYYY-MM-DD HH24:MI:SS'), 
+			b.status,
+			b.aircraft_type,
+			COALESCE(b.destination_code, ''),
+			COALESCE(al.name, '')
+		FROM Board b
+		LEFT JOIN Airlines al ON al.iata = UPPER(LEFT(b.flightnumber, 2))
 	`
 
 	rows, err := db.Query(ctx, query)
@@ -110,9 +185,13 @@ func (b *Board) GetBoard(db *pgxpool.Pool) ([]Board, error) {
 			&boardItem.Appointment,
 			&boardItem.Departure,
 			&boardItem.Status,
+			&boardItem.AircraftType,
+			&boardItem.DestinationCode,
+			&boardItem.AirlineName,
 		); err != nil {
 			return nil, err
 		}
+		boardItem.StatusText = i18n.T(ctx, boardItem.Status)
 		boardRows = append(boardRows, boardItem)
 	}
 
@@ -123,28 +202,36 @@ func (b *Board) GetBoard(db *pgxpool.Pool) ([]Board, error) {
 	return boardRows, nil
 }
 
-func (b *Board) ChangeFlightStatus(db *pgxpool.Pool) error {
-	ctx := context.Background()
-
+func (b *Board) ChangeFlightStatus(ctx context.Context, db *pgxpool.Pool) error {
 	query := `
 		UPDATE Board
 		SET
 			status = $1,
-			status_change_time = NOW()
+			status_change_time = NOW(),
+			actual_departure = CASE
+				WHEN $1 = $3 THEN COALESCE(actual_departure, NOW())
+				ELSE actual_departure
+			END
 		WHERE id = $2
 	`
 
-	_, err := db.Exec(ctx, query, b.Status, b.Id)
+	result, err := db.Exec(ctx, query, b.Status, b.Id, StatusDeparted)
 	if err != nil {
 		return fmt.Errorf("ошибка при обновлении статуса: %w", err)
 	}
+	if result.RowsAffected() == 0 {
+		return errFlightNotFound
+	}
+
+	// Изменённый вручную рейс генератор по расписанию больше не пересоздаёт
+	if _, err := db.Exec(ctx, `UPDATE Flight_Schedule_Days SET edited = true WHERE board_id = $1`, b.Id); err != nil {
+		return fmt.Errorf("ошибка при обновлении статуса: %w", err)
+	}
 
 	return nil
 }
 
-func (b *Board) SelectDeparturePoint(db *pgxpool.Pool) ([]string, error) {
-	ctx := context.Background()
-
+func (b *Board) SelectDeparturePoint(ctx context.Context, db *pgxpool.Pool) ([]string, error) {
 	query := `
 		SELECT appointment 
 		FROM Board
@@ -171,12 +258,11 @@ func (b *Board) SelectDeparturePoint(db *pgxpool.Pool) ([]string, error) {
 	return startLocations, nil
 }
 
-func (b *Board) SelectDepartureEndPoint(db *pgxpool.Pool, startLocation string) ([]string, error) {
-	ctx := context.Background()
-
+func (b *Board) SelectDepartureEndPoint(ctx context.Context, db *pgxpool.Pool, startLocation string) ([]string, error) {
 	query := `
 		SELECT appointment 
 		FROM Board
+		WHERE appointment LIKE $1
 	`
 
 	rows, err := db.Query(ctx, query, startLocation+"%")
@@ -204,11 +290,9 @@ func (b *Board) SelectDepartureEndPoint(db *pgxpool.Pool, startLocation string)
 	return endLocations, nil
 }
 
-func (b *Board) SelectAllFlight(db *pgxpool.Pool) ([]Board, error) {
-	ctx := context.Background()
-
+func (b *Board) SelectAllFlight(ctx context.Context, db *pgxpool.Pool) ([]Board, error) {
 	query := `
-    	SELECT id, appointment 
+    	SELECT id, appointment, COALESCE(destination_code, '')
     	FROM Board
     	WHERE status = 'Регистрация'
     `
@@ -222,7 +306,7 @@ func (b *Board) SelectAllFlight(db *pgxpool.Pool) ([]Board, error) {
 	var startLocations []Board
 	for rows.Next() {
 		var boardItem Board
-		if err := rows.Scan(&boardItem.Id, &boardItem.Appointment); err != nil {
+		if err := rows.Scan(&boardItem.Id, &boardItem.Appointment, &boardItem.DestinationCode); err != nil {
 			return nil, err
 		}
 		startLocations = append(startLocations, boardItem)
@@ -234,3 +318,35 @@ func (b *Board) SelectAllFlight(db *pgxpool.Pool) ([]Board, error) {
 
 	return startLocations, nil
 }
+
+func (b *Board) CountByStatus(ctx context.Context, db *pgxpool.Pool) (map[string]int, error) {
+	query := `
+		SELECT status, COUNT(*)
+		FROM Board
+		GROUP BY status
+	`
+
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при подсчёте рейсов: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var (
+			status string
+			count  int
+		)
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("ошибка при подсчёте рейсов: %w", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при подсчёте рейсов: %w", err)
+	}
+
+	return counts, nil
+}
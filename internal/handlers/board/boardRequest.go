@@ -0,0 +1,74 @@
+package board
+
+import (
+	"AirPort/internal/handlers/user"
+	"strings"
+)
+
+type FlightRequest struct {
+	FlightNumber string `json:"flightNumber" binding:"required,flightnumber"`
+	// Appointment можно не передавать, если задан destinationCode: название возьмётся из справочника
+	Appointment  string `json:"appointment" binding:"omitempty,max=255"`
+	Departure    string `json:"departure" binding:"required,datetime=2006-01-02 15:04:05,future"`
+	SeatCapacity int    `json:"seatCapacity" binding:"omitempty,min=1,max=1000"`
+	AircraftType string `json:"aircraftType" binding:"omitempty,max=10"`
+	// DestinationCode — код IATA аэропорта назначения из справочника
+	DestinationCode string `json:"destinationCode" binding:"omitempty,iata"`
+}
+
+func (r FlightRequest) Board() Board {
+	return Board{
+		FlightNumber:    strings.ToUpper(r.FlightNumber),
+		Appointment:     r.Appointment,
+		Departure:       r.Departure,
+		SeatCapacity:    r.SeatCapacity,
+		AircraftType:    strings.ToUpper(r.AircraftType),
+		DestinationCode: strings.ToUpper(r.DestinationCode),
+	}
+}
+
+type FlightStatusRequest struct {
+	Id     int    `json:"id" binding:"required,gt=0"`
+	Status string `json:"status" binding:"required,max=50"`
+}
+
+type StatusRequest struct {
+	Status string `json:"status" binding:"required,max=50"`
+}
+
+type FlightIDRequest struct {
+	Id int `json:"id" binding:"required,gt=0"`
+}
+
+type CreateFlightRequest struct {
+	User  user.Credentials `json:"user"`
+	Board FlightRequest    `json:"board"`
+}
+
+type UpdateStatusRequest struct {
+	User  user.Credentials    `json:"user"`
+	Board FlightStatusRequest `json:"board"`
+}
+
+type DeleteFlightRequest struct {
+	User  user.Credentials `json:"user"`
+	Board FlightIDRequest  `json:"board"`
+}
+
+type EndRoutesRequest struct {
+	StartLocation string `json:"startLocation" binding:"required"`
+}
+
+type DestinationsRequest struct {
+	From string `form:"from" binding:"required"`
+}
+
+// ImportRequest — параметры импорта, сам файл передаётся телом запроса
+type ImportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ssim"`
+	DryRun bool   `form:"dryRun"`
+	// Origin — для SSIM: импортировать только вылеты из этого аэропорта
+	Origin string `form:"origin" binding:"omitempty,iata"`
+	// HorizonDays — для SSIM: на сколько дней вперёд разворачивать периоды, по умолчанию как у расписаний рейсов
+	HorizonDays int `form:"horizonDays" binding:"omitempty,min=1,max=366"`
+}
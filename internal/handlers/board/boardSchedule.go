@@ -0,0 +1,425 @@
+package board
+
+import (
+	"AirPort/package/apperrors"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	ScheduleTimeFormat = "15:04"
+	ScheduleDateFormat = "2006-01-02"
+)
+
+var errScheduleNotFound = apperrors.NotFound("расписание рейса не найдено")
+
+// Schedule — регулярный рейс. Генератор создаёт по нему рейсы на табло на несколько дней вперёд
+type Schedule struct {
+	Id           int    `db:"id" json:"id"`
+	FlightNumber string `db:"flight_number" json:"flightNumber" binding:"required,flightnumber"`
+	Origin       string `db:"origin" json:"origin" binding:"omitempty,max=100"`
+	Destination  string `db:"destination" json:"destination" binding:"required,max=255"`
+	// DaysOfWeek — дни выполнения, понедельник — 1, воскресенье — 7
+	DaysOfWeek    []int     `db:"days_of_week" json:"daysOfWeek" binding:"required,min=1,max=7,dive,min=1,max=7"`
+	DepartureTime string    `db:"departure_time" json:"departureTime" binding:"required,datetime=15:04"`
+	ArrivalTime   string    `db:"arrival_time" json:"arrivalTime,omitempty" binding:"omitempty,datetime=15:04"`
+	ValidFrom     string    `db:"valid_from" json:"validFrom" binding:"required,datetime=2006-01-02"`
+	ValidTo       string    `db:"valid_to" json:"validTo,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	AircraftType  string    `db:"aircraft_type" json:"aircraftType,omitempty" binding:"omitempty,max=10"`
+	SeatCapacity  int       `db:"seat_capacity" json:"seatCapacity,omitempty" binding:"omitempty,min=1,max=1000"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+const scheduleColumns = `
+	id,
+	flight_number,
+	origin,
+	destination,
+	days_of_week,
+	TO_CHAR(departure_time, 'HH24:MI'),
+	COALESCE(TO_CHAR(arrival_time, 'HH24:MI'), ''),
+	TO_CHAR(valid_from, 'YYYY-MM-DD'),
+	COALESCE(TO_CHAR(valid_to, 'YYYY-MM-DD'), ''),
+	aircraft_type,
+	seat_capacity,
+	created_at
+`
+
+func (s *Schedule) Validate() error {
+	if s.ValidTo != "" && s.ValidTo < s.ValidFrom {
+		return apperrors.Field("validTo", "дата окончания раньше даты начала")
+	}
+
+	s.FlightNumber = strings.ToUpper(s.FlightNumber)
+	s.AircraftType = strings.ToUpper(s.AircraftType)
+	if s.SeatCapacity <= 0 {
+		s.SeatCapacity = DefaultSeatCapacity
+	}
+
+	return nil
+}
+
+func (s *Schedule) scan(row pgx.Row) error {
+	return row.Scan(
+		&s.Id,
+		&s.FlightNumber,
+		&s.Origin,
+		&s.Destination,
+		&s.DaysOfWeek,
+		&s.DepartureTime,
+		&s.ArrivalTime,
+		&s.ValidFrom,
+		&s.ValidTo,
+		&s.AircraftType,
+		&s.SeatCapacity,
+		&s.CreatedAt,
+	)
+}
+
+func (s *Schedule) Create(ctx context.Context, db *pgxpool.Pool) error {
+	query := `
+		INSERT INTO Flight_Schedules (flight_number, origin, destination, days_of_week, departure_time, arrival_time, valid_from, valid_to, aircraft_type, seat_capacity)
+		VALUES ($1, $2, $3, $4, $5::text::time, NULLIF($6::text, '')::time, $7::text::date, NULLIF($8::text, '')::date, $9, $10)
+		RETURNING id, created_at
+	`
+
+	if err := db.QueryRow(
+		ctx,
+		query,
+		s.FlightNumber,
+		s.Origin,
+		s.Destination,
+		s.DaysOfWeek,
+		s.DepartureTime,
+		s.ArrivalTime,
+		s.ValidFrom,
+		s.ValidTo,
+		s.AircraftType,
+		s.SeatCapacity,
+	).Scan(&s.Id, &s.CreatedAt); err != nil {
+		return fmt.Errorf("ошибка при создании расписания рейса: %w", err)
+	}
+
+	return nil
+}
+
+// Update меняет расписание и снимает с табло будущие рейсы, созданные по старому.
+// Рейсы, изменённые вручную или с проданными билетами, остаются как есть
+func (s *Schedule) Update(ctx context.Context, db *pgxpool.Pool) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении расписания рейса: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE Flight_Schedules
+		SET flight_number = $2, origin = $3, destination = $4, days_of_week = $5,
+			departure_time = $6::text::time, arrival_time = NULLIF($7::text, '')::time,
+			valid_from = $8::text::date, valid_to = NULLIF($9::text, '')::date,
+			aircraft_type = $10, seat_capacity = $11
+		WHERE id = $1
+		RETURNING created_at
+	`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		s.Id,
+		s.FlightNumber,
+		s.Origin,
+		s.Destination,
+		s.DaysOfWeek,
+		s.DepartureTime,
+		s.ArrivalTime,
+		s.ValidFrom,
+		s.ValidTo,
+		s.AircraftType,
+		s.SeatCapacity,
+	).Scan(&s.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errScheduleNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении расписания рейса: %w", err)
+	}
+
+	if err := releaseDays(ctx, tx, s.Id, time.Now()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка при обновлении расписания рейса: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет расписание вместе с будущими рейсами, которые никто не трогал
+func (s *Schedule) Delete(ctx context.Context, db *pgxpool.Pool) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении расписания рейса: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := releaseDays(ctx, tx, s.Id, time.Now()); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM Flight_Schedules WHERE id = $1`, s.Id)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении расписания рейса: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errScheduleNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("ошибка при удалении расписания рейса: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Schedule) Get(ctx context.Context, db *pgxpool.Pool) (*Schedule, error) {
+	var schedule Schedule
+	err := schedule.scan(db.QueryRow(ctx, `SELECT `+scheduleColumns+` FROM Flight_Schedules WHERE id = $1`, s.Id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errScheduleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении расписания рейса: %w", err)
+	}
+
+	return &schedule, nil
+}
+
+func (s *Schedule) GetAll(ctx context.Context, db *pgxpool.Pool) ([]Schedule, error) {
+	rows, err := db.Query(ctx, `SELECT `+scheduleColumns+` FROM Flight_Schedules ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении расписаний рейсов: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []Schedule
+	for rows.Next() {
+		var schedule Schedule
+		if err := schedule.scan(rows); err != nil {
+			return nil, fmt.Errorf("ошибка при получении расписаний рейсов: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при получении расписаний рейсов: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// departures возвращает вылеты по расписанию после now и не позже конца дня until.
+// Время вылета местное, как и у остальных рейсов на табло
+func (s *Schedule) departures(now, until time.Time) ([]time.Time, error) {
+	from, err := time.ParseInLocation(ScheduleDateFormat, s.ValidFrom, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("неверная дата начала расписания %d: %w", s.Id, err)
+	}
+	last := until
+	if s.ValidTo != "" {
+		to, err := time.ParseInLocation(ScheduleDateFormat, s.ValidTo, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("неверная дата окончания расписания %d: %w", s.Id, err)
+		}
+		if to.Before(last) {
+			last = to
+		}
+	}
+	clock, err := time.Parse(ScheduleTimeFormat, s.DepartureTime)
+	if err != nil {
+		return nil, fmt.Errorf("неверное время вылета расписания %d: %w", s.Id, err)
+	}
+
+	var days [8]bool
+	for _, day := range s.DaysOfWeek {
+		if day >= 1 && day <= 7 {
+			days[day] = true
+		}
+	}
+
+	year, month, day := now.Date()
+	if today := time.Date(year, month, day, 0, 0, 0, 0, time.Local); from.Before(today) {
+		from = today
+	}
+
+	var departures []time.Time
+	for date := from; !date.After(last); date = date.AddDate(0, 0, 1) {
+		// Понедельник — 1, воскресенье — 7, как в SSIM
+		if !days[(int(date.Weekday())+6)%7+1] {
+			continue
+		}
+		departure := time.Date(date.Year(), date.Month(), date.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+		if departure.After(now) {
+			departures = append(departures, departure)
+		}
+	}
+
+	return departures, nil
+}
+
+// materialise создаёт рейсы по расписанию на дни до until, для которых их ещё не создавали.
+// День, на который рейс с тем же номером уже добавлен вручную, отмечается изменённым
+func (s *Schedule) materialise(ctx context.Context, db *pgxpool.Pool, now, until time.Time) (int, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Расписание, которое сейчас меняют или заполняет другой экземпляр, пропускается до следующего прохода
+	err = s.scan(tx.QueryRow(ctx, `SELECT `+scheduleColumns+` FROM Flight_Schedules WHERE id = $1 FOR UPDATE SKIP LOCKED`, s.Id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+	}
+
+	departures, err := s.departures(now, until)
+	if err != nil || len(departures) == 0 {
+		return 0, err
+	}
+
+	existing := make(map[string]bool)
+	rows, err := tx.Query(ctx, `
+		SELECT TO_CHAR(flight_date, 'YYYY-MM-DD')
+		FROM Flight_Schedule_Days
+		WHERE schedule_id = $1 AND flight_date >= $2::text::date
+	`, s.Id, departures[0].Format(ScheduleDateFormat))
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+	}
+	for rows.Next() {
+		var date string
+		if err := rows.Scan(&date); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+		}
+		existing[date] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+	}
+
+	created := 0
+	for _, departure := range departures {
+		date := departure.Format(ScheduleDateFormat)
+		if existing[date] {
+			continue
+		}
+
+		flight := Board{
+			FlightNumber: s.FlightNumber,
+			Appointment:  s.Destination,
+			Departure:    departure.Format(TimeFormat),
+			SeatCapacity: s.SeatCapacity,
+			AircraftType: s.AircraftType,
+		}
+		var boardId *int
+		err := flight.insert(ctx, tx)
+		switch {
+		case err == nil:
+			boardId = &flight.Id
+			created++
+		case errors.Is(err, apperrors.ErrConflict):
+		default:
+			return 0, err
+		}
+
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO Flight_Schedule_Days (schedule_id, flight_date, board_id, edited)
+			VALUES ($1, $2::text::date, $3, $4)
+		`, s.Id, date, boardId, boardId == nil); err != nil {
+			return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("ошибка при заполнении табло по расписанию: %w", err)
+	}
+
+	return created, nil
+}
+
+// releaseDays снимает с табло будущие рейсы расписания, которые не меняли вручную
+// и на которые нет билетов, чтобы генератор создал их заново
+func releaseDays(ctx context.Context, tx pgx.Tx, scheduleId int, now time.Time) error {
+	query := `
+		WITH released AS (
+			DELETE FROM Flight_Schedule_Days d
+			USING Board b
+			WHERE d.schedule_id = $1
+				AND d.board_id = b.id
+				AND NOT d.edited
+				AND b.departure > $2
+				AND NOT EXISTS (SELECT 1 FROM Tickets t WHERE t.flightId = b.id)
+			RETURNING d.board_id
+		)
+		DELETE FROM Board
+		WHERE id IN (SELECT board_id FROM released)
+	`
+
+	if _, err := tx.Exec(ctx, query, scheduleId, now); err != nil {
+		return fmt.Errorf("ошибка при снятии рейсов расписания с табло: %w", err)
+	}
+
+	return nil
+}
+
+// Materialise заполняет табло по всем расписаниям на horizonDays дней вперёд
+func Materialise(ctx context.Context, db *pgxpool.Pool, now time.Time, horizonDays int) (int, error) {
+	rows, err := db.Query(ctx, `SELECT id FROM Flight_Schedules ORDER BY id`)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при получении расписаний рейсов: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("ошибка при получении расписаний рейсов: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("ошибка при получении расписаний рейсов: %w", err)
+	}
+
+	year, month, day := now.Date()
+	until := time.Date(year, month, day, 0, 0, 0, 0, time.Local).AddDate(0, 0, horizonDays)
+
+	created := 0
+	var errs []error
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		schedule := Schedule{Id: id}
+		n, err := schedule.materialise(ctx, db, now, until)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		created += n
+	}
+
+	return created, errors.Join(errs...)
+}
@@ -0,0 +1,153 @@
+package board
+
+import (
+	"reflect"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+// withLocal подменяет часовой пояс процесса: расписание считается в местном времени
+func withLocal(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("часовой пояс %s: %v", name, err)
+	}
+	local := time.Local
+	time.Local = location
+	t.Cleanup(func() { time.Local = local })
+
+	return location
+}
+
+func TestScheduleDepartures(t *testing.T) {
+	berlin := withLocal(t, "Europe/Berlin")
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		until    time.Time
+		want     []string
+	}{
+		{
+			name:     "понедельник — 1",
+			schedule: Schedule{DaysOfWeek: []int{1}, DepartureTime: "09:00", ValidFrom: "2026-10-01"},
+			now:      at(2026, 10, 18, 12, 0),
+			until:    at(2026, 11, 1, 0, 0),
+			want:     []string{"2026-10-19 09:00 CEST", "2026-10-26 09:00 CET"},
+		},
+		{
+			name:     "воскресенье — 7",
+			schedule: Schedule{DaysOfWeek: []int{7}, DepartureTime: "21:15", ValidFrom: "2026-10-01"},
+			now:      at(2026, 10, 17, 12, 0),
+			until:    at(2026, 11, 1, 0, 0),
+			want:     []string{"2026-10-18 21:15 CEST", "2026-10-25 21:15 CET", "2026-11-01 21:15 CET"},
+		},
+		{
+			name:     "несколько дней и дни вне диапазона",
+			schedule: Schedule{DaysOfWeek: []int{0, 3, 5, 8}, DepartureTime: "07:40", ValidFrom: "2026-10-01"},
+			now:      at(2026, 10, 19, 0, 0),
+			until:    at(2026, 10, 26, 0, 0),
+			want:     []string{"2026-10-21 07:40 CEST", "2026-10-23 07:40 CEST"},
+		},
+		{
+			name:     "valid_to раньше горизонта",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-10-01", ValidTo: "2026-10-21"},
+			now:      at(2026, 10, 19, 0, 0),
+			until:    at(2026, 11, 1, 0, 0),
+			want:     []string{"2026-10-19 09:00 CEST", "2026-10-20 09:00 CEST", "2026-10-21 09:00 CEST"},
+		},
+		{
+			name:     "valid_to позже горизонта, день until входит целиком",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-10-01", ValidTo: "2026-12-31"},
+			now:      at(2026, 10, 19, 0, 0),
+			until:    at(2026, 10, 20, 0, 0),
+			want:     []string{"2026-10-19 09:00 CEST", "2026-10-20 09:00 CEST"},
+		},
+		{
+			name:     "расписание закончилось",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-09-01", ValidTo: "2026-09-30"},
+			now:      at(2026, 10, 19, 0, 0),
+			until:    at(2026, 11, 1, 0, 0),
+		},
+		{
+			name:     "расписание ещё не началось",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-10-31"},
+			now:      at(2026, 10, 19, 0, 0),
+			until:    at(2026, 11, 1, 0, 0),
+			want:     []string{"2026-10-31 09:00 CET", "2026-11-01 09:00 CET"},
+		},
+		{
+			name:     "сегодняшний вылет уже прошёл",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-10-01"},
+			now:      at(2026, 10, 19, 9, 0),
+			until:    at(2026, 10, 20, 0, 0),
+			want:     []string{"2026-10-20 09:00 CEST"},
+		},
+		{
+			name:     "переход на летнее время: время вылета сохраняется",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-03-01"},
+			now:      at(2026, 3, 28, 0, 0),
+			until:    at(2026, 3, 30, 0, 0),
+			want:     []string{"2026-03-28 09:00 CET", "2026-03-29 09:00 CEST", "2026-03-30 09:00 CEST"},
+		},
+		{
+			name:     "переход на летнее время: несуществующий час сдвигается вперёд",
+			schedule: Schedule{DaysOfWeek: []int{7}, DepartureTime: "02:30", ValidFrom: "2026-03-01"},
+			now:      at(2026, 3, 28, 0, 0),
+			until:    at(2026, 3, 30, 0, 0),
+			want:     []string{"2026-03-29 03:30 CEST"},
+		},
+		{
+			name:     "переход на зимнее время: время вылета сохраняется",
+			schedule: Schedule{DaysOfWeek: []int{1, 2, 3, 4, 5, 6, 7}, DepartureTime: "09:00", ValidFrom: "2026-10-01"},
+			now:      at(2026, 10, 24, 0, 0),
+			until:    at(2026, 10, 26, 0, 0),
+			want:     []string{"2026-10-24 09:00 CEST", "2026-10-25 09:00 CET", "2026-10-26 09:00 CET"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			departures, err := tt.schedule.departures(tt.now, tt.until)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			var got []string
+			for _, departure := range departures {
+				got = append(got, departure.Format("2006-01-02 15:04 MST"))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("вылеты %v, ожидались %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleDeparturesErrors(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+	}{
+		{"неверная дата начала", Schedule{DaysOfWeek: []int{1}, DepartureTime: "09:00", ValidFrom: "19.10.2026"}},
+		{"неверная дата окончания", Schedule{DaysOfWeek: []int{1}, DepartureTime: "09:00", ValidFrom: "2026-10-01", ValidTo: "2026-13-01"}},
+		{"неверное время вылета", Schedule{DaysOfWeek: []int{1}, DepartureTime: "25:00", ValidFrom: "2026-10-01"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.schedule.departures(now, now.AddDate(0, 0, 7)); err == nil {
+				t.Error("ожидалась ошибка")
+			}
+		})
+	}
+}
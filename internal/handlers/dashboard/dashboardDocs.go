@@ -0,0 +1,35 @@
+package dashboard
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+// limitQuery описывает параметр limit, который topRoutesLimit разбирает вручную
+type limitQuery struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+type topRoutesResponse struct {
+	Routes []RouteKPI `json:"routes"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Дашборд"
+
+	description := "Ответ кэшируется, срок указан в заголовке Cache-Control. Только для сотрудников."
+	routes := map[string]openapi.Operation{
+		"/dashboard/summary":    {Summary: "Сводка показателей за сутки", Query: limitQuery{}, Response: Summary{}},
+		"/dashboard/flights":    {Summary: "Рейсы по статусам", Response: FlightsKPI{}},
+		"/dashboard/passengers": {Summary: "Загрузка рейсов", Response: PassengersKPI{}},
+		"/dashboard/revenue":    {Summary: "Выручка по сравнению с прошлой неделей", Response: RevenueKPI{}},
+		"/dashboard/routes":     {Summary: "Популярные направления", Query: limitQuery{}, Response: topRoutesResponse{}},
+		"/dashboard/delays":     {Summary: "Задержки вылетов", Response: DelayKPI{}},
+	}
+
+	for path, op := range routes {
+		op.Tag, op.Description, op.Auth = tag, description, true
+		router.DocV1(http.MethodGet, path, op)
+	}
+}
@@ -0,0 +1,129 @@
+package dashboard
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/cache"
+	"AirPort/package/database"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const loadTimeout = 10 * time.Second
+
+type Handler struct {
+	db    *pgxpool.Pool
+	cache *cache.Cache[any]
+}
+
+func NewHandler(db *pgxpool.Pool, cacheTTL time.Duration) handlers.Handlers {
+	return &Handler{db: db, cache: cache.New[any](cacheTTL)}
+}
+
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	routes := map[string]gin.HandlerFunc{
+		"/dashboard/summary":    h.GetSummary,
+		"/dashboard/flights":    h.GetFlights,
+		"/dashboard/passengers": h.GetPassengers,
+		"/dashboard/revenue":    h.GetRevenue,
+		"/dashboard/routes":     h.GetTopRoutes,
+		"/dashboard/delays":     h.GetDelays,
+	}
+
+	for path, handler := range routes {
+		router.V1.GET(path, auth.RequireEmployee(), handler)
+	}
+}
+
+// respond отдаёт показатель из кэша, чтобы частые обновления дашборда не нагружали БД
+func (h *Handler) respond(c *gin.Context, key string, load func(ctx context.Context) (any, error)) {
+	// Результат ждут и другие запросы, поэтому отмена первого из них не должна прерывать загрузку
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), loadTimeout)
+	defer cancel()
+
+	data, err := h.cache.Get(key, func() (any, error) {
+		return database.RetryRead(ctx, h.db, func(ctx context.Context, _ *pgxpool.Pool) (any, error) {
+			return load(ctx)
+		})
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Показатели доступны только сотрудникам, поэтому общие кэши их хранить не должны
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", int(h.cache.TTL().Seconds())))
+	c.JSON(http.StatusOK, data)
+}
+
+func (h *Handler) GetSummary(c *gin.Context) {
+	limit, ok := topRoutesLimit(c)
+	if !ok {
+		return
+	}
+
+	h.respond(c, "summary:"+strconv.Itoa(limit), func(ctx context.Context) (any, error) {
+		return GetSummary(ctx, h.db, limit)
+	})
+}
+
+func (h *Handler) GetFlights(c *gin.Context) {
+	h.respond(c, "flights", func(ctx context.Context) (any, error) {
+		return GetFlights(ctx, h.db)
+	})
+}
+
+func (h *Handler) GetPassengers(c *gin.Context) {
+	h.respond(c, "passengers", func(ctx context.Context) (any, error) {
+		return GetPassengers(ctx, h.db)
+	})
+}
+
+func (h *Handler) GetRevenue(c *gin.Context) {
+	h.respond(c, "revenue", func(ctx context.Context) (any, error) {
+		return GetRevenue(ctx, h.db)
+	})
+}
+
+func (h *Handler) GetTopRoutes(c *gin.Context) {
+	limit, ok := topRoutesLimit(c)
+	if !ok {
+		return
+	}
+
+	h.respond(c, "routes:"+strconv.Itoa(limit), func(ctx context.Context) (any, error) {
+		routes, err := GetTopRoutes(ctx, h.db, limit)
+		if err != nil {
+			return nil, err
+		}
+		return gin.H{"routes": routes}, nil
+	})
+}
+
+func (h *Handler) GetDelays(c *gin.Context) {
+	h.respond(c, "delays", func(ctx context.Context) (any, error) {
+		return GetDelays(ctx, h.db)
+	})
+}
+
+func topRoutesLimit(c *gin.Context) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return DefaultTopRoutes, true
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > MaxTopRoutes {
+		c.Error(apperrors.Validation("limit должен быть от 1 до %d", MaxTopRoutes))
+		return 0, false
+	}
+
+	return limit, true
+}
@@ -0,0 +1,242 @@
+package dashboard
+
+import (
+	"AirPort/internal/handlers/board"
+	"AirPort/internal/handlers/report"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	DefaultTopRoutes = 5
+	MaxTopRoutes     = 20
+)
+
+// Все показатели считаются за текущие сутки по часам сервера БД
+const today = "b.departure >= CURRENT_DATE AND b.departure < CURRENT_DATE + 1"
+
+type FlightsKPI struct {
+	Total    int            `json:"total"`
+	ByStatus map[string]int `json:"byStatus"`
+}
+
+type PassengersKPI struct {
+	Booked     int     `json:"booked"`
+	Seats      int     `json:"seats"`
+	LoadFactor float64 `json:"loadFactor"`
+}
+
+// RevenueKPI сравнивает выручку с начала суток с тем же отрезком неделю назад
+type RevenueKPI struct {
+	Today         int      `json:"today"`
+	LastWeek      int      `json:"lastWeek"`
+	ChangePercent *float64 `json:"changePercent"`
+	TicketsSold   int      `json:"ticketsSold"`
+}
+
+type RouteKPI struct {
+	Route      string `json:"route"`
+	Flights    int    `json:"flights"`
+	Passengers int    `json:"passengers"`
+	Revenue    int    `json:"revenue"`
+}
+
+type DelayKPI struct {
+	Departed      int     `json:"departed"`
+	AvgDelay      float64 `json:"avgDelay"`
+	OnTimePercent float64 `json:"onTimePercent"`
+}
+
+type Summary struct {
+	Date        string        `json:"date"`
+	Flights     FlightsKPI    `json:"flights"`
+	Passengers  PassengersKPI `json:"passengers"`
+	Revenue     RevenueKPI    `json:"revenue"`
+	TopRoutes   []RouteKPI    `json:"topRoutes"`
+	Delays      DelayKPI      `json:"delays"`
+	GeneratedAt time.Time     `json:"generatedAt"`
+}
+
+func GetFlights(ctx context.Context, db *pgxpool.Pool) (*FlightsKPI, error) {
+	query := fmt.Sprintf(`
+		SELECT b.status, COUNT(*)
+		FROM Board b
+		WHERE %s
+		GROUP BY b.status
+	`, today)
+
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения рейсов по статусам: %w", err)
+	}
+	defer rows.Close()
+
+	kpi := &FlightsKPI{ByStatus: make(map[string]int)}
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		kpi.ByStatus[status] = count
+		kpi.Total += count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	return kpi, nil
+}
+
+func GetPassengers(ctx context.Context, db *pgxpool.Pool) (*PassengersKPI, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			COALESCE(SUM(booked), 0)::INT,
+			COALESCE(SUM(capacity), 0)::INT
+		FROM (
+			SELECT COUNT(t.id) AS booked, MAX(b.seat_capacity) AS capacity
+			FROM Board b
+			LEFT JOIN Tickets t ON t.flightId = b.id
+			WHERE %s AND b.status <> $1
+			GROUP BY b.id
+		) flights
+	`, today)
+
+	var kpi PassengersKPI
+	if err := db.QueryRow(ctx, query, board.StatusCanceled).Scan(&kpi.Booked, &kpi.Seats); err != nil {
+		return nil, fmt.Errorf("ошибка получения числа пассажиров: %w", err)
+	}
+	if kpi.Seats > 0 {
+		kpi.LoadFactor = float64(kpi.Booked) * 100 / float64(kpi.Seats)
+	}
+
+	return &kpi, nil
+}
+
+// GetRevenue считает выручку по дате покупки; билеты без неё (проданные до
+// появления created_at) в выручку за день и неделю не попадают
+func GetRevenue(ctx context.Context, db *pgxpool.Pool) (*RevenueKPI, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(price) FILTER (WHERE created_at >= CURRENT_DATE), 0)::INT,
+			COALESCE(SUM(price) FILTER (WHERE created_at < NOW() - INTERVAL '7 days'), 0)::INT,
+			COUNT(*) FILTER (WHERE created_at >= CURRENT_DATE)
+		FROM Tickets
+		WHERE created_at >= CURRENT_DATE - 7
+			AND (created_at >= CURRENT_DATE OR created_at < CURRENT_DATE - 6)
+	`
+
+	var kpi RevenueKPI
+	if err := db.QueryRow(ctx, query).Scan(&kpi.Today, &kpi.LastWeek, &kpi.TicketsSold); err != nil {
+		return nil, fmt.Errorf("ошибка получения выручки: %w", err)
+	}
+	if kpi.LastWeek > 0 {
+		change := float64(kpi.Today-kpi.LastWeek) * 100 / float64(kpi.LastWeek)
+		kpi.ChangePercent = &change
+	}
+
+	return &kpi, nil
+}
+
+func GetTopRoutes(ctx context.Context, db *pgxpool.Pool, limit int) ([]RouteKPI, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			b.appointment,
+			COUNT(DISTINCT b.id),
+			COUNT(t.id),
+			COALESCE(SUM(t.price), 0)::INT
+		FROM Board b
+		LEFT JOIN Tickets t ON t.flightId = b.id
+		WHERE %s
+		GROUP BY b.appointment
+		ORDER BY COUNT(t.id) DESC, b.appointment
+		LIMIT $1
+	`, today)
+
+	rows, err := db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения популярных направлений: %w", err)
+	}
+	defer rows.Close()
+
+	routes := []RouteKPI{}
+	for rows.Next() {
+		var route RouteKPI
+		if err := rows.Scan(&route.Route, &route.Flights, &route.Passengers, &route.Revenue); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		routes = append(routes, route)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	return routes, nil
+}
+
+// GetDelays считает задержку так же, как отчёт о пунктуальности
+func GetDelays(ctx context.Context, db *pgxpool.Pool) (*DelayKPI, error) {
+	query := fmt.Sprintf(`
+		WITH delays AS (
+			SELECT EXTRACT(EPOCH FROM (
+				COALESCE(b.actual_departure, b.status_change_time) - b.departure
+			)) / 60 AS delay
+			FROM Board b
+			WHERE %s AND (b.actual_departure IS NOT NULL OR b.status = $1)
+		)
+		SELECT
+			COUNT(*),
+			COALESCE(AVG(delay), 0)::FLOAT,
+			COALESCE(COUNT(*) FILTER (WHERE delay <= %d) * 100.0 / NULLIF(COUNT(*), 0), 0)::FLOAT
+		FROM delays
+	`, today, report.OnTimeThreshold)
+
+	var kpi DelayKPI
+	if err := db.QueryRow(ctx, query, board.StatusDeparted).Scan(&kpi.Departed, &kpi.AvgDelay, &kpi.OnTimePercent); err != nil {
+		return nil, fmt.Errorf("ошибка получения задержек: %w", err)
+	}
+
+	return &kpi, nil
+}
+
+func GetSummary(ctx context.Context, db *pgxpool.Pool, topRoutes int) (*Summary, error) {
+	summary := &Summary{
+		Date:        time.Now().Format(report.DateFormat),
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	flights, err := GetFlights(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	summary.Flights = *flights
+
+	passengers, err := GetPassengers(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	summary.Passengers = *passengers
+
+	revenue, err := GetRevenue(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	summary.Revenue = *revenue
+
+	if summary.TopRoutes, err = GetTopRoutes(ctx, db, topRoutes); err != nil {
+		return nil, err
+	}
+
+	delays, err := GetDelays(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	summary.Delays = *delays
+
+	return summary, nil
+}
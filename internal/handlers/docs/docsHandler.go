@@ -0,0 +1,65 @@
+package docs
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	specPath = "/openapi.json"
+	uiPath   = "/docs"
+)
+
+type Handler struct {
+	spec *openapi.Spec
+
+	once sync.Once
+	body []byte
+	err  error
+}
+
+func NewHandler(spec *openapi.Spec) handlers.Handlers {
+	return &Handler{spec: spec}
+}
+
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.GET(specPath, h.GetSpec)
+	router.GET(uiPath, h.GetUI)
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Служебные"
+
+	router.Doc(http.MethodGet, specPath, openapi.Operation{
+		Tag: tag, Summary: "Описание API в формате OpenAPI " + openapi.Version, ContentType: "application/json",
+	})
+	router.Doc(http.MethodGet, uiPath, openapi.Operation{
+		Tag: tag, Summary: "Swagger UI", ContentType: "text/html",
+	})
+}
+
+// GetSpec собирает документ при первом запросе: к этому моменту все маршруты уже описаны
+func (h *Handler) GetSpec(c *gin.Context) {
+	h.once.Do(func() {
+		h.body, h.err = json.Marshal(h.spec)
+	})
+	if h.err != nil {
+		c.Error(h.err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", h.body)
+}
+
+func (h *Handler) GetUI(c *gin.Context) {
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	c.Status(http.StatusOK)
+	if err := openapi.WriteUI(c.Writer, "AirPort API", specPath); err != nil {
+		c.Error(err)
+	}
+}
@@ -1,9 +1,116 @@
 package handlers
 
 import (
+	"AirPort/internal/config"
+	"AirPort/package/apperrors"
+	"AirPort/package/openapi"
+	"AirPort/package/tracing"
+	"AirPort/package/validation"
+	"net/http"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
+// APIPrefix — префикс текущей версии API
+const APIPrefix = "/api/v1"
+
+// Handlers регистрирует маршруты и описывает их в OpenAPI. Маршрут без описания
+// не пройдёт проверку в cmd/routes_test.go
 type Handlers interface {
-	RegisterHandler(router *gin.Engine)
+	RegisterHandler(router *Router)
+	Document(router *Router)
+}
+
+// Router передаёт обработчикам группу /api/v1 и регистрирует старые маршруты
+// как устаревшие. Служебные маршруты вроде /healthz вешаются прямо на Engine
+type Router struct {
+	*gin.Engine
+	V1   *gin.RouterGroup
+	Docs *openapi.Spec
+
+	legacy   bool
+	sunset   string
+	disabled map[string]bool
+}
+
+// TokenResponse и MessageResponse описывают общие ответы в документации
+type TokenResponse struct {
+	Token string `json:"token"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+func NewRouter(engine *gin.Engine, cfg config.APIConfig) *Router {
+	r := &Router{
+		Engine:   engine,
+		V1:       engine.Group(APIPrefix),
+		Docs:     openapi.New("AirPort API", "1.0", "API табло, билетов и отчётов аэропорта", apperrors.Response{}),
+		legacy:   cfg.LegacyRoutes,
+		disabled: make(map[string]bool),
+	}
+	if sunset, err := time.Parse(time.DateOnly, cfg.LegacySunset); err == nil {
+		r.sunset = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return r
+}
+
+// Deprecated регистрирует старый маршрут. Ответ получает заголовок Deprecation
+// и ссылку на замену в /api/v1; если старые маршруты отключены, маршрут не создаётся
+func (r *Router) Deprecated(method, path, successor string, handlers ...gin.HandlerFunc) {
+	r.Docs.Deprecate(method, path, APIPrefix+successor)
+	if !r.legacy {
+		r.disabled[method+" "+path] = true
+		return
+	}
+
+	link := "<" + APIPrefix + successor + `>; rel="successor-version"`
+	deprecated := func(c *gin.Context) {
+		c.Header("Deprecation", "true")
+		c.Header("Link", link)
+		if r.sunset != "" {
+			c.Header("Sunset", r.sunset)
+		}
+	}
+
+	r.Handle(method, path, append([]gin.HandlerFunc{deprecated}, handlers...)...)
+}
+
+// Doc описывает маршрут с полным путём, как он виден в gin
+func (r *Router) Doc(method, path string, op openapi.Operation) {
+	if r.disabled[method+" "+path] {
+		return
+	}
+
+	r.Docs.Add(method, path, op)
+}
+
+// DocV1 описывает маршрут группы /api/v1
+func (r *Router) DocV1(method, path string, op openapi.Operation) {
+	r.Doc(method, APIPrefix+path, op)
+}
+
+// BindJSON читает и проверяет тело запроса в отдельном спане, чтобы видеть время разбора JSON
+func BindJSON(c *gin.Context, obj any) error {
+	_, span := tracing.Start(c.Request.Context(), "json.bind")
+	defer span.End()
+
+	if err := c.ShouldBindJSON(obj); err != nil {
+		span.RecordError(err)
+		return validation.FromError(err)
+	}
+
+	return nil
+}
+
+// BindQuery читает и проверяет параметры строки запроса
+func BindQuery(c *gin.Context, obj any) error {
+	if err := c.ShouldBindQuery(obj); err != nil {
+		return validation.FromError(err)
+	}
+
+	return nil
 }
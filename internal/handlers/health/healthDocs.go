@@ -0,0 +1,32 @@
+package health
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+type readinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+type livenessResponse struct {
+	Status string `json:"status"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Служебные"
+
+	router.Doc(http.MethodGet, "/healthz", openapi.Operation{
+		Tag: tag, Summary: "Проверка живости", Response: livenessResponse{},
+	})
+	router.Doc(http.MethodGet, "/readyz", openapi.Operation{
+		Tag: tag, Summary: "Проверка готовности",
+		Description: "Код 503 и ошибки проверок, если сервер не готов принимать трафик.",
+		Response:    readinessResponse{},
+	})
+	router.Doc(http.MethodGet, "/metrics", openapi.Operation{
+		Tag: tag, Summary: "Метрики Prometheus", ContentType: "text/plain",
+	})
+}
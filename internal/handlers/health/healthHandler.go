@@ -0,0 +1,45 @@
+package health
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/health"
+	"AirPort/package/metrics"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const checkTimeout = 2 * time.Second
+
+type Handler struct {
+	checker *health.Checker
+}
+
+func NewHandler(checker *health.Checker) handlers.Handlers {
+	return &Handler{checker: checker}
+}
+
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.GET("/healthz", h.Liveness)
+	router.GET("/readyz", h.Readiness)
+	router.GET("/metrics", metrics.Handler())
+}
+
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func (h *Handler) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+	defer cancel()
+
+	checks, ready := h.checker.Ready(ctx)
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": checks})
+}
@@ -0,0 +1,51 @@
+package reference
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+type airportsResponse struct {
+	Airports []Airport `json:"airports"`
+}
+
+type airlinesResponse struct {
+	Airlines []Airline `json:"airlines"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Справочники"
+
+	router.DocV1(http.MethodGet, "/airports", openapi.Operation{
+		Tag: tag, Summary: "Поиск аэропортов",
+		Description: "По точному коду IATA или ICAO, части названия или города. Совпадения по коду идут первыми.",
+		Query:       AirportSearchRequest{}, Response: airportsResponse{},
+	})
+	router.DocV1(http.MethodGet, "/airports/:code", openapi.Operation{
+		Tag: tag, Summary: "Аэропорт по коду IATA или ICAO",
+		Response: Airport{},
+	})
+	router.DocV1(http.MethodPost, "/airports/import", openapi.Operation{
+		Tag: tag, Summary: "Загрузка справочника аэропортов",
+		Description: "CSV в формате airports.csv из OurAirports: iata_code, icao_code или gps_code, name, municipality, iso_country, " +
+			"latitude_deg, longitude_deg; часовой пояс — в колонке timezone. Аэропорты без кода IATA и закрытые пропускаются, " +
+			"известные обновляются по коду IATA. Если хоть одна строка с ошибкой, не загружается ничего. Только для сотрудников.",
+		Auth: true, Query: LoadRequest{}, BodyTypes: []string{"text/csv"}, Response: LoadResult{},
+	})
+	router.DocV1(http.MethodGet, "/airlines", openapi.Operation{
+		Tag: tag, Summary: "Поиск авиакомпаний",
+		Description: "По точному коду IATA или ICAO или части названия.",
+		Query:       AirlineSearchRequest{}, Response: airlinesResponse{},
+	})
+	router.DocV1(http.MethodGet, "/airlines/:code", openapi.Operation{
+		Tag: tag, Summary: "Авиакомпания по коду IATA или ICAO",
+		Response: Airline{},
+	})
+	router.DocV1(http.MethodPost, "/airlines/import", openapi.Operation{
+		Tag: tag, Summary: "Загрузка справочника авиакомпаний",
+		Description: "CSV с колонками iata, icao, name, country; строки с active=N пропускаются. " +
+			"Известные авиакомпании обновляются по коду IATA. Если хоть одна строка с ошибкой, не загружается ничего. Только для сотрудников.",
+		Auth: true, Query: LoadRequest{}, BodyTypes: []string{"text/csv"}, Response: LoadResult{},
+	})
+}
@@ -0,0 +1,167 @@
+package reference
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// maxLoadSize ограничивает размер файла справочника: airports.csv из OurAirports — около 12 МБ
+const maxLoadSize = 32 << 20
+
+type Handler struct {
+	db *pgxpool.Pool
+}
+
+func NewHandler(db *pgxpool.Pool) handlers.Handlers {
+	return &Handler{db: db}
+}
+
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.V1.GET("/airports", h.SearchAirports)
+	router.V1.GET("/airports/:code", h.GetAirport)
+	router.V1.POST("/airports/import", auth.RequireEmployee(), h.LoadAirports)
+	router.V1.GET("/airlines", h.SearchAirlines)
+	router.V1.GET("/airlines/:code", h.GetAirline)
+	router.V1.POST("/airlines/import", auth.RequireEmployee(), h.LoadAirlines)
+}
+
+func (h *Handler) SearchAirports(c *gin.Context) {
+	var request AirportSearchRequest
+	if err := handlers.BindQuery(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	airports, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) ([]Airport, error) {
+		return SearchAirports(ctx, db, request)
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"airports": airports})
+}
+
+// GetAirport ищет аэропорт по коду IATA из трёх букв или ICAO из четырёх символов
+func (h *Handler) GetAirport(c *gin.Context) {
+	code := c.Param("code")
+	if len(code) != 3 && len(code) != 4 {
+		c.Error(errAirportNotFound)
+		return
+	}
+
+	airport, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) (*Airport, error) {
+		return FindAirport(ctx, db, code)
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, airport)
+}
+
+func (h *Handler) SearchAirlines(c *gin.Context) {
+	var request AirlineSearchRequest
+	if err := handlers.BindQuery(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	airlines, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) ([]Airline, error) {
+		return SearchAirlines(ctx, db, request)
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"airlines": airlines})
+}
+
+// GetAirline ищет авиакомпанию по коду IATA из двух символов или ICAO из трёх букв
+func (h *Handler) GetAirline(c *gin.Context) {
+	code := c.Param("code")
+	if len(code) != 2 && len(code) != 3 {
+		c.Error(errAirlineNotFound)
+		return
+	}
+
+	airline, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) (*Airline, error) {
+		return FindAirline(ctx, db, code)
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, airline)
+}
+
+func (h *Handler) LoadAirports(c *gin.Context) {
+	var request LoadRequest
+	if err := handlers.BindQuery(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	file, err := ParseAirports(limitBody(c))
+	if err != nil {
+		c.Error(tooLarge(err))
+		return
+	}
+
+	result, err := LoadAirports(c.Request.Context(), h.db, file, request.DryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) LoadAirlines(c *gin.Context) {
+	var request LoadRequest
+	if err := handlers.BindQuery(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	file, err := ParseAirlines(limitBody(c))
+	if err != nil {
+		c.Error(tooLarge(err))
+		return
+	}
+
+	result, err := LoadAirlines(c.Request.Context(), h.db, file, request.DryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func limitBody(c *gin.Context) io.Reader {
+	return http.MaxBytesReader(c.Writer, c.Request.Body, maxLoadSize)
+}
+
+// tooLarge заменяет ошибку чтения слишком большого тела понятным сообщением
+func tooLarge(err error) error {
+	var maxBytes *http.MaxBytesError
+	if errors.As(err, &maxBytes) {
+		return apperrors.BadRequest("файл справочника больше %d МБ", maxLoadSize>>20)
+	}
+
+	return err
+}
@@ -0,0 +1,268 @@
+package reference
+
+import (
+	"AirPort/package/apperrors"
+	"AirPort/package/csvtable"
+	"AirPort/package/validation"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// MaxLoadRows ограничивает размер одной транзакции загрузки справочника
+const MaxLoadRows = 20000
+
+var icaoCode = regexp.MustCompile(validation.Patterns["icao"])
+
+// Row — запись справочника и строка файла, на которую указывают ошибки
+type Row[T any] struct {
+	Line  int
+	Value T
+
+	err error
+}
+
+// File — записи из файла справочника. Skipped — строки без кода IATA и закрытые аэропорты
+type File[T any] struct {
+	Rows    []Row[T]
+	Skipped int
+}
+
+type LoadResult struct {
+	DryRun  bool `json:"dryRun"`
+	Total   int  `json:"total"`
+	Loaded  int  `json:"loaded"`
+	Skipped int  `json:"skipped"`
+}
+
+// table дополняет csvtable.Table колонками с несколькими именами
+type table struct {
+	*csvtable.Table
+}
+
+func newTable(r io.Reader, required ...[]string) (*table, error) {
+	t, err := csvtable.New(r, required...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &table{Table: t}, nil
+}
+
+// value возвращает первое непустое значение из колонок с этими именами.
+// \N — пустое значение в выгрузках OpenFlights
+func (t *table) value(names ...string) string {
+	for _, name := range names {
+		if v := t.Value(name); v != "" && v != `\N` {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// coordinate разбирает широту или долготу, пустое значение — нет координаты
+func (t *table) coordinate(field string, names ...string) (*float64, error) {
+	v := t.value(names...)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return nil, apperrors.Field(field, "должно быть числом")
+	}
+
+	return &f, nil
+}
+
+// ParseAirports читает аэропорты из CSV в формате OurAirports (airports.csv): iata_code, icao_code
+// или gps_code, name, municipality, iso_country, latitude_deg, longitude_deg. Допускаются и короткие
+// имена колонок iata, icao, city, country, latitude, longitude; часовой пояс — в колонке timezone или tz
+func ParseAirports(r io.Reader) (*File[Airport], error) {
+	t, err := newTable(r, []string{"iata_code", "iata"}, []string{"name"})
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File[Airport]{}
+	for {
+		line, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// В OurAirports есть тысячи площадок без кода IATA, рейсы на них не выполняются
+		iata := t.value("iata_code", "iata")
+		if iata == "" || strings.EqualFold(t.value("type"), "closed") {
+			file.Skipped++
+			continue
+		}
+
+		row := Row[Airport]{Line: line, Value: Airport{
+			Iata:     strings.ToUpper(iata),
+			Name:     t.value("name"),
+			City:     t.value("municipality", "city"),
+			Country:  strings.ToUpper(t.value("iso_country", "country")),
+			Timezone: t.value("timezone", "tz", "tz_database_time_zone"),
+		}}
+		// gps_code и ident в OurAirports бывают и локальными кодами, поэтому ICAO — первый четырёхсимвольный код
+		for _, name := range []string{"icao_code", "icao", "gps_code"} {
+			if code := strings.ToUpper(t.value(name)); icaoCode.MatchString(code) {
+				row.Value.Icao = code
+				break
+			}
+		}
+		if row.Value.Latitude, err = t.coordinate("latitude", "latitude_deg", "latitude", "lat"); err == nil {
+			row.Value.Longitude, err = t.coordinate("longitude", "longitude_deg", "longitude", "lon")
+		}
+		row.err = err
+
+		file.Rows = append(file.Rows, row)
+	}
+
+	return file, nil
+}
+
+// ParseAirlines читает авиакомпании из CSV с колонками iata, icao, name и country.
+// Строки с active=N пропускаются
+func ParseAirlines(r io.Reader) (*File[Airline], error) {
+	t, err := newTable(r, []string{"iata", "iata_code"}, []string{"name"})
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File[Airline]{}
+	for {
+		line, err := t.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		iata := t.value("iata", "iata_code")
+		if iata == "" || iata == "-" || strings.EqualFold(t.value("active"), "N") {
+			file.Skipped++
+			continue
+		}
+
+		file.Rows = append(file.Rows, Row[Airline]{Line: line, Value: Airline{
+			Iata:    strings.ToUpper(iata),
+			Icao:    strings.ToUpper(t.value("icao", "icao_code")),
+			Name:    t.value("name"),
+			Country: t.value("country"),
+		}})
+	}
+
+	return file, nil
+}
+
+// LoadAirports добавляет аэропорты в справочник или обновляет уже известные по коду IATA
+func LoadAirports(ctx context.Context, db *pgxpool.Pool, file *File[Airport], dryRun bool) (*LoadResult, error) {
+	query := `
+		INSERT INTO Airports (iata, icao, name, city, country, timezone, latitude, longitude)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (iata) DO UPDATE
+		SET icao = EXCLUDED.icao, name = EXCLUDED.name, city = EXCLUDED.city, country = EXCLUDED.country,
+			timezone = EXCLUDED.timezone, latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, updated_at = NOW()
+	`
+
+	return load(ctx, db, file, dryRun, func(batch *pgx.Batch, a Airport) {
+		batch.Queue(query, a.Iata, a.Icao, a.Name, a.City, a.Country, a.Timezone, a.Latitude, a.Longitude)
+	})
+}
+
+// LoadAirlines добавляет авиакомпании в справочник или обновляет уже известные по коду IATA
+func LoadAirlines(ctx context.Context, db *pgxpool.Pool, file *File[Airline], dryRun bool) (*LoadResult, error) {
+	query := `
+		INSERT INTO Airlines (iata, icao, name, country)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (iata) DO UPDATE
+		SET icao = EXCLUDED.icao, name = EXCLUDED.name, country = EXCLUDED.country, updated_at = NOW()
+	`
+
+	return load(ctx, db, file, dryRun, func(batch *pgx.Batch, a Airline) {
+		batch.Queue(query, a.Iata, a.Icao, a.Name, a.Country)
+	})
+}
+
+// load проверяет все записи и загружает их в одной транзакции. Если хоть одна строка
+// не прошла проверку, не загружается ничего. При dryRun база не нужна: db может быть nil
+func load[T any](ctx context.Context, db *pgxpool.Pool, file *File[T], dryRun bool, queue func(*pgx.Batch, T)) (*LoadResult, error) {
+	if len(file.Rows) == 0 {
+		return nil, apperrors.BadRequest("в файле нет записей для загрузки")
+	}
+	if len(file.Rows) > MaxLoadRows {
+		return nil, apperrors.BadRequest("в файле больше %d записей, разделите его на части", MaxLoadRows)
+	}
+
+	var fields []apperrors.FieldError
+	for _, row := range file.Rows {
+		err := row.err
+		if err == nil {
+			err = validation.Struct(row.Value)
+		}
+		if err != nil {
+			fields = append(fields, rowErrors(row.Line, err)...)
+		}
+	}
+	if len(fields) > 0 {
+		return nil, apperrors.Validation("файл справочника содержит ошибки, данные не загружены").WithFields(fields...)
+	}
+
+	result := &LoadResult{DryRun: dryRun, Total: len(file.Rows) + file.Skipped, Loaded: len(file.Rows), Skipped: file.Skipped}
+	if dryRun {
+		return result, nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, row := range file.Rows {
+		queue(batch, row.Value)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при начале загрузки справочника: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	results := tx.SendBatch(ctx, batch)
+	for _, row := range file.Rows {
+		if _, err := results.Exec(); err != nil {
+			results.Close()
+			return nil, fmt.Errorf("ошибка при загрузке строки %d справочника: %w", row.Line, err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка при загрузке справочника: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка при фиксации загрузки справочника: %w", err)
+	}
+
+	return result, nil
+}
+
+// rowErrors привязывает ошибки записи к строке файла: line[5].iata
+func rowErrors(line int, err error) []apperrors.FieldError {
+	prefix := fmt.Sprintf("line[%d]", line)
+
+	appErr := apperrors.From(err)
+	if len(appErr.Fields) == 0 {
+		return []apperrors.FieldError{appErr.AsField(prefix)}
+	}
+
+	return appErr.WithFieldPrefix(prefix).Fields
+}
@@ -0,0 +1,271 @@
+package reference
+
+import (
+	"AirPort/package/apperrors"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func float(f float64) *float64 {
+	return &f
+}
+
+func TestParseAirports(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Airport
+		skipped int
+	}{
+		{
+			name: "формат OurAirports",
+			data: `"id","ident","type","name","latitude_deg","longitude_deg","iso_country","municipality","gps_code","iata_code","icao_code"
+4334,"UUEE","large_airport","Sheremetyevo International Airport",55.972599,37.4146,"RU","Moscow","UUEE","SVO","UUEE"
+`,
+			want: []Airport{{
+				Iata: "SVO", Icao: "UUEE", Name: "Sheremetyevo International Airport", City: "Moscow", Country: "RU",
+				Latitude: float(55.972599), Longitude: float(37.4146),
+			}},
+		},
+		{
+			name: "короткие имена колонок и часовой пояс",
+			data: "iata,icao,name,city,country,latitude,longitude,tz\nled,ulli,Pulkovo,Saint Petersburg,ru,59.8,30.26,Europe/Moscow\n",
+			want: []Airport{{
+				Iata: "LED", Icao: "ULLI", Name: "Pulkovo", City: "Saint Petersburg", Country: "RU", Timezone: "Europe/Moscow",
+				Latitude: float(59.8), Longitude: float(30.26),
+			}},
+		},
+		{
+			name: "колонки OpenFlights и \\N вместо пустого значения",
+			data: "name,city,country,iata,icao,lat,lon,tz_database_time_zone\nGoroka Airport,Goroka,PG,GKA,\\N,-6.08,145.39,\\N\n",
+			want: []Airport{{
+				Iata: "GKA", Name: "Goroka Airport", City: "Goroka", Country: "PG",
+				Latitude: float(-6.08), Longitude: float(145.39),
+			}},
+		},
+		{
+			name: "ICAO из gps_code, если icao_code пуст",
+			data: "iata_code,icao_code,gps_code,name\nAER,,URSS,Sochi\n",
+			want: []Airport{{Iata: "AER", Icao: "URSS", Name: "Sochi"}},
+		},
+		{
+			name: "локальный gps_code не считается ICAO",
+			data: "iata_code,icao_code,gps_code,name\nXYZ,,X1,Local strip\n",
+			want: []Airport{{Iata: "XYZ", Name: "Local strip"}},
+		},
+		{
+			name: "icao_code важнее gps_code",
+			data: "iata_code,icao_code,gps_code,name\nVKO,UUWW,UUEE,Vnukovo\n",
+			want: []Airport{{Iata: "VKO", Icao: "UUWW", Name: "Vnukovo"}},
+		},
+		{
+			name: "без кода IATA и закрытые пропускаются",
+			data: `iata_code,type,name
+,small_airport,Field
+\N,heliport,Pad
+TXL,closed,Tegel
+BER,large_airport,Brandenburg
+`,
+			want:    []Airport{{Iata: "BER", Name: "Brandenburg"}},
+			skipped: 3,
+		},
+		{
+			name: "BOM в заголовке",
+			data: "\ufeffiata,name\nKZN,Kazan\n",
+			want: []Airport{{Iata: "KZN", Name: "Kazan"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ParseAirports(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			var got []Airport
+			for _, row := range file.Rows {
+				if row.err != nil {
+					t.Fatalf("строка %d: %v", row.Line, row.err)
+				}
+				got = append(got, row.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("аэропорты %+v, ожидались %+v", got, tt.want)
+			}
+			if file.Skipped != tt.skipped {
+				t.Errorf("пропущено %d, ожидалось %d", file.Skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestParseAirportsCoordinates(t *testing.T) {
+	file, err := ParseAirports(strings.NewReader("iata,name,latitude,longitude\nSVO,Sheremetyevo,north,37.4\nLED,Pulkovo,59.8,east\n"))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	var fields []string
+	for _, row := range file.Rows {
+		for _, field := range rowErrors(row.Line, row.err) {
+			fields = append(fields, field.Field)
+		}
+	}
+	if want := []string{"line[2].latitude", "line[3].longitude"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("ошибки %v, ожидались %v", fields, want)
+	}
+}
+
+func TestParseAirlines(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Airline
+		skipped int
+	}{
+		{
+			name: "формат OpenFlights",
+			data: `"id","name","alias","iata","icao","callsign","country","active"
+130,"Aeroflot Russian Airlines",\N,"SU","AFL","AEROFLOT","Russia","Y"
+`,
+			want: []Airline{{Iata: "SU", Icao: "AFL", Name: "Aeroflot Russian Airlines", Country: "Russia"}},
+		},
+		{
+			name: "код IATA с цифрой и строчные буквы",
+			data: "iata_code,icao_code,name\nu6,svr,Ural Airlines\n",
+			want: []Airline{{Iata: "U6", Icao: "SVR", Name: "Ural Airlines"}},
+		},
+		{
+			name: "\\N вместо ICAO",
+			data: "iata,icao,name\nS7,\\N,Siberia Airlines\n",
+			want: []Airline{{Iata: "S7", Name: "Siberia Airlines"}},
+		},
+		{
+			name: "неактивные и без кода IATA пропускаются",
+			data: `iata,icao,name,active
+SU,AFL,Aeroflot,Y
+UN,TSO,Transaero,N
+,XXX,No code,Y
+-,YYY,Dash,Y
+\N,ZZZ,Null,Y
+`,
+			want:    []Airline{{Iata: "SU", Icao: "AFL", Name: "Aeroflot"}},
+			skipped: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ParseAirlines(strings.NewReader(tt.data))
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			var got []Airline
+			for _, row := range file.Rows {
+				got = append(got, row.Value)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("авиакомпании %+v, ожидались %+v", got, tt.want)
+			}
+			if file.Skipped != tt.skipped {
+				t.Errorf("пропущено %d, ожидалось %d", file.Skipped, tt.skipped)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) error
+		data  string
+	}{
+		{"пустой файл", parseAirports, ""},
+		{"нет колонки iata", parseAirports, "icao,name\nUUEE,Sheremetyevo\n"},
+		{"нет колонки name", parseAirlines, "iata,icao\nSU,AFL\n"},
+		{"разделитель точка с запятой", parseAirlines, "iata;icao;name\n"},
+		{"незакрытая кавычка", parseAirports, "iata,name\nSVO,\"Sheremetyevo\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.parse(tt.data); !errors.Is(err, apperrors.ErrBadRequest) {
+				t.Errorf("ожидалась ошибка запроса, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestParseMalformedRow(t *testing.T) {
+	tests := []struct {
+		name  string
+		parse func(string) error
+		data  string
+		line  int
+	}{
+		{"незакрытая кавычка в начале поля", parseAirports, "iata_code,name\n\"SVO,x\n", 2},
+		{"кавычка внутри поля", parseAirports, "iata_code,name\nLED,Pulkovo\nS\"VO,x\n", 3},
+		{"незакрытая кавычка в авиакомпаниях", parseAirlines, "iata,name\nSU,Aeroflot\n\"S7,x\n", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.parse(tt.data)
+
+			var appErr *apperrors.Error
+			if !errors.As(err, &appErr) || !errors.Is(err, apperrors.ErrBadRequest) {
+				t.Fatalf("ожидалась ошибка запроса, получено %v", err)
+			}
+			if want := fmt.Sprintf("строке %d", tt.line); !strings.Contains(appErr.Error(), want) {
+				t.Errorf("ошибка %q не указывает на строку %d", appErr.Error(), tt.line)
+			}
+		})
+	}
+}
+
+func TestLoadDryRun(t *testing.T) {
+	file, err := ParseAirports(strings.NewReader("iata,icao,name,country\nSVO,UUEE,Sheremetyevo,RU\nLED,ULLI,,RU\nKZN,UWKD,Kazan,RUS\n,,Field,RU\n"))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	_, err = LoadAirports(context.Background(), nil, file, true)
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) || appErr.Status() != http.StatusUnprocessableEntity {
+		t.Fatalf("ожидалась ошибка проверки, получено %v", err)
+	}
+
+	var fields []string
+	for _, field := range appErr.Fields {
+		fields = append(fields, field.Field)
+	}
+	if want := []string{"line[3].name", "line[4].country"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("ошибки %v, ожидались %v", fields, want)
+	}
+
+	file.Rows = []Row[Airport]{file.Rows[0]}
+	result, err := LoadAirports(context.Background(), nil, file, true)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if want := (LoadResult{DryRun: true, Total: 2, Loaded: 1, Skipped: 1}); *result != want {
+		t.Errorf("результат %+v, ожидался %+v", *result, want)
+	}
+}
+
+func parseAirports(data string) error {
+	_, err := ParseAirports(strings.NewReader(data))
+	return err
+}
+
+func parseAirlines(data string) error {
+	_, err := ParseAirlines(strings.NewReader(data))
+	return err
+}
@@ -0,0 +1,159 @@
+package reference
+
+import (
+	"AirPort/package/apperrors"
+	"AirPort/package/database"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+var (
+	errAirportNotFound = apperrors.NotFound("аэропорт не найден")
+	errAirlineNotFound = apperrors.NotFound("авиакомпания не найдена")
+
+	likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+type Airport struct {
+	Iata      string   `db:"iata" json:"iata" binding:"required,iata"`
+	Icao      string   `db:"icao" json:"icao,omitempty" binding:"omitempty,icao"`
+	Name      string   `db:"name" json:"name" binding:"required,max=255"`
+	City      string   `db:"city" json:"city,omitempty" binding:"max=255"`
+	Country   string   `db:"country" json:"country,omitempty" binding:"omitempty,len=2"`
+	Timezone  string   `db:"timezone" json:"timezone,omitempty" binding:"max=64"`
+	Latitude  *float64 `db:"latitude" json:"latitude,omitempty" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `db:"longitude" json:"longitude,omitempty" binding:"omitempty,min=-180,max=180"`
+}
+
+type Airline struct {
+	Iata    string `db:"iata" json:"iata" binding:"required,airline"`
+	Icao    string `db:"icao" json:"icao,omitempty" binding:"omitempty,airlineicao"`
+	Name    string `db:"name" json:"name" binding:"required,max=255"`
+	Country string `db:"country" json:"country,omitempty" binding:"max=100"`
+}
+
+const (
+	airportColumns = `iata, icao, name, city, country, timezone, latitude, longitude`
+	airlineColumns = `iata, icao, name, country`
+)
+
+func (a *Airport) scan(row pgx.Row) error {
+	return row.Scan(&a.Iata, &a.Icao, &a.Name, &a.City, &a.Country, &a.Timezone, &a.Latitude, &a.Longitude)
+}
+
+func (a *Airline) scan(row pgx.Row) error {
+	return row.Scan(&a.Iata, &a.Icao, &a.Name, &a.Country)
+}
+
+// FindAirport ищет аэропорт по коду IATA или ICAO
+func FindAirport(ctx context.Context, db database.Querier, code string) (*Airport, error) {
+	column := "iata"
+	if len(code) == 4 {
+		column = "icao"
+	}
+
+	var airport Airport
+	err := airport.scan(db.QueryRow(ctx, `SELECT `+airportColumns+` FROM Airports WHERE `+column+` = $1 LIMIT 1`, strings.ToUpper(code)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errAirportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске аэропорта: %w", err)
+	}
+
+	return &airport, nil
+}
+
+// FindAirline ищет авиакомпанию по коду IATA или ICAO
+func FindAirline(ctx context.Context, db database.Querier, code string) (*Airline, error) {
+	column := "iata"
+	if len(code) == 3 {
+		column = "icao"
+	}
+
+	var airline Airline
+	err := airline.scan(db.QueryRow(ctx, `SELECT `+airlineColumns+` FROM Airlines WHERE `+column+` = $1 LIMIT 1`, strings.ToUpper(code)))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errAirlineNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске авиакомпании: %w", err)
+	}
+
+	return &airline, nil
+}
+
+// SearchAirports ищет по точному коду, а также по части названия или города.
+// Совпадения по коду идут первыми
+func SearchAirports(ctx context.Context, db *pgxpool.Pool, request AirportSearchRequest) ([]Airport, error) {
+	query := `
+		SELECT ` + airportColumns + `
+		FROM Airports
+		WHERE ($1::text = '' OR iata = UPPER($1) OR icao = UPPER($1) OR name ILIKE $2 OR city ILIKE $2)
+			AND ($3::text = '' OR country = UPPER($3))
+		ORDER BY iata = UPPER($1) DESC, icao = UPPER($1) DESC, city, iata
+		LIMIT $4
+	`
+
+	rows, err := db.Query(ctx, query, request.Query, "%"+likeEscaper.Replace(request.Query)+"%", request.Country, request.limit())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске аэропортов: %w", err)
+	}
+	defer rows.Close()
+
+	airports := []Airport{}
+	for rows.Next() {
+		var airport Airport
+		if err := airport.scan(rows); err != nil {
+			return nil, fmt.Errorf("ошибка при поиске аэропортов: %w", err)
+		}
+		airports = append(airports, airport)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при поиске аэропортов: %w", err)
+	}
+
+	return airports, nil
+}
+
+func SearchAirlines(ctx context.Context, db *pgxpool.Pool, request AirlineSearchRequest) ([]Airline, error) {
+	query := `
+		SELECT ` + airlineColumns + `
+		FROM Airlines
+		WHERE $1::text = '' OR iata = UPPER($1) OR icao = UPPER($1) OR name ILIKE $2
+		ORDER BY iata = UPPER($1) DESC, icao = UPPER($1) DESC, name, iata
+		LIMIT $3
+	`
+
+	rows, err := db.Query(ctx, query, request.Query, "%"+likeEscaper.Replace(request.Query)+"%", request.limit())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске авиакомпаний: %w", err)
+	}
+	defer rows.Close()
+
+	airlines := []Airline{}
+	for rows.Next() {
+		var airline Airline
+		if err := airline.scan(rows); err != nil {
+			return nil, fmt.Errorf("ошибка при поиске авиакомпаний: %w", err)
+		}
+		airlines = append(airlines, airline)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при поиске авиакомпаний: %w", err)
+	}
+
+	return airlines, nil
+}
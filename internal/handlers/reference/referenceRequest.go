@@ -0,0 +1,35 @@
+package reference
+
+type AirportSearchRequest struct {
+	// Query — код IATA или ICAO, часть названия или города
+	Query   string `form:"q" binding:"omitempty,max=100"`
+	Country string `form:"country" binding:"omitempty,len=2"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r AirportSearchRequest) limit() int {
+	if r.Limit == 0 {
+		return DefaultSearchLimit
+	}
+
+	return r.Limit
+}
+
+type AirlineSearchRequest struct {
+	// Query — код IATA или ICAO или часть названия
+	Query string `form:"q" binding:"omitempty,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r AirlineSearchRequest) limit() int {
+	if r.Limit == 0 {
+		return DefaultSearchLimit
+	}
+
+	return r.Limit
+}
+
+// LoadRequest — параметры загрузки справочника, сам файл передаётся телом запроса
+type LoadRequest struct {
+	DryRun bool `form:"dryRun"`
+}
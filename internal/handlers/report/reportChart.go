@@ -0,0 +1,141 @@
+package report
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	ChartLine = "line"
+	ChartBar  = "bar"
+
+	chartGridLines = 4
+	chartMaxLabels = 12
+)
+
+type Chart struct {
+	Title  string    `json:"title"`
+	Kind   string    `json:"kind"`
+	Labels []string  `json:"labels"`
+	Values []float64 `json:"values"`
+}
+
+// newChart строит график по строкам отчёта одного типа
+func newChart[T Row](title, kind string, rows []Row, label func(T) string, value func(T) float64) Chart {
+	chart := Chart{Title: title, Kind: kind}
+	for _, row := range rows {
+		typed, ok := row.(T)
+		if !ok {
+			continue
+		}
+		chart.Labels = append(chart.Labels, label(typed))
+		chart.Values = append(chart.Values, value(typed))
+	}
+
+	return chart
+}
+
+func drawChart(pdf *gofpdf.Fpdf, chart Chart, x, y, w, h float64) {
+	const (
+		titleHeight = 7.0
+		axisWidth   = 16.0
+		labelHeight = 8.0
+	)
+
+	pdf.SetFont(fontFamily, "B", 10)
+	pdf.SetXY(x, y)
+	pdf.CellFormat(w, titleHeight, chart.Title, "", 0, "L", false, 0, "")
+
+	plotX := x + axisWidth
+	plotY := y + titleHeight + 2
+	plotW := w - axisWidth
+	plotH := h - titleHeight - labelHeight - 2
+
+	if len(chart.Values) == 0 {
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.SetXY(plotX, plotY+plotH/2)
+		pdf.CellFormat(plotW, 5, "Нет данных", "", 0, "C", false, 0, "")
+		return
+	}
+
+	minValue, maxValue := chartRange(chart.Values)
+	toY := func(v float64) float64 {
+		return plotY + plotH - (v-minValue)/(maxValue-minValue)*plotH
+	}
+
+	// Сетка и подписи оси значений
+	pdf.SetFont(fontFamily, "", 7)
+	pdf.SetDrawColor(220, 220, 220)
+	for i := 0; i <= chartGridLines; i++ {
+		v := minValue + (maxValue-minValue)*float64(i)/chartGridLines
+		gy := toY(v)
+		pdf.Line(plotX, gy, plotX+plotW, gy)
+		pdf.SetXY(x, gy-2)
+		pdf.CellFormat(axisWidth-1, 4, formatAxis(v, maxValue-minValue), "", 0, "R", false, 0, "")
+	}
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.Line(plotX, plotY, plotX, plotY+plotH)
+	pdf.Line(plotX, toY(math.Max(minValue, 0)), plotX+plotW, toY(math.Max(minValue, 0)))
+
+	step := plotW / float64(len(chart.Values))
+	pdf.SetFillColor(brandColor[0], brandColor[1], brandColor[2])
+	pdf.SetDrawColor(brandColor[0], brandColor[1], brandColor[2])
+
+	switch chart.Kind {
+	case ChartBar:
+		zero := toY(math.Max(minValue, 0))
+		for i, v := range chart.Values {
+			top := math.Min(toY(v), zero)
+			pdf.Rect(plotX+step*float64(i)+step*0.15, top, step*0.7, math.Abs(zero-toY(v)), "F")
+		}
+	default:
+		pdf.SetLineWidth(0.5)
+		for i, v := range chart.Values {
+			cx := plotX + step*float64(i) + step/2
+			if i > 0 {
+				pdf.Line(plotX+step*float64(i-1)+step/2, toY(chart.Values[i-1]), cx, toY(v))
+			}
+			pdf.Circle(cx, toY(v), 0.7, "F")
+		}
+		pdf.SetLineWidth(0.2)
+	}
+	pdf.SetDrawColor(0, 0, 0)
+
+	// Подписи периодов: не больше chartMaxLabels, чтобы не наезжали друг на друга
+	every := int(math.Ceil(float64(len(chart.Labels)) / chartMaxLabels))
+	pdf.SetFont(fontFamily, "", 6)
+	for i, label := range chart.Labels {
+		if i%every != 0 {
+			continue
+		}
+		pdf.SetXY(plotX+step*float64(i), plotY+plotH+1)
+		pdf.CellFormat(step*float64(every), 4, label, "", 0, "C", false, 0, "")
+	}
+}
+
+// chartRange возвращает границы оси значений с нулём внутри диапазона
+func chartRange(values []float64) (float64, float64) {
+	minValue, maxValue := 0.0, 0.0
+	for _, v := range values {
+		minValue = math.Min(minValue, v)
+		maxValue = math.Max(maxValue, v)
+	}
+	if maxValue == minValue {
+		maxValue = minValue + 1
+	}
+
+	return minValue, maxValue
+}
+
+func formatAxis(v, span float64) string {
+	switch {
+	case span >= 10000:
+		return fmt.Sprintf("%.0fk", v/1000)
+	case span >= 10:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return fmt.Sprintf("%.1f", v)
+	}
+}
@@ -0,0 +1,81 @@
+package report
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+	"strings"
+)
+
+type jobCreatedResponse struct {
+	JobId  string `json:"jobId"`
+	Status string `json:"status"`
+}
+
+type jobsResponse struct {
+	Jobs []Job `json:"jobs"`
+}
+
+type schedulesResponse struct {
+	Schedules []Schedule `json:"schedules"`
+}
+
+type runsResponse struct {
+	Runs []ScheduleRun `json:"runs"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Отчёты"
+
+	download := "Файл в формате, указанном при создании отчёта: pdf, csv, xlsx или json."
+	routes := []struct {
+		method, path, legacy string
+		op                   openapi.Operation
+	}{
+		{http.MethodPost, "/reports/jobs", "/report/generateReport", openapi.Operation{
+			Summary: "Постановка отчёта в очередь", Description: "Адрес задачи возвращается в заголовке Location.",
+			Body: Report{}, Status: http.StatusAccepted, Response: jobCreatedResponse{},
+		}},
+		{http.MethodGet, "/reports/jobs", "/report/jobs", openapi.Operation{
+			Summary: "История задач отчётов", Response: jobsResponse{},
+		}},
+		{http.MethodGet, "/reports/jobs/:id", "/report/jobs/:id", openapi.Operation{
+			Summary: "Состояние задачи", Response: Job{},
+		}},
+		{http.MethodGet, "/reports/jobs/:id/download", "/report/jobs/:id/download", openapi.Operation{
+			Summary: "Скачивание готового отчёта", Description: download, ContentType: "application/octet-stream",
+		}},
+		{http.MethodDelete, "/reports/jobs/:id", "/report/jobs/:id", openapi.Operation{
+			Summary: "Отмена задачи", Response: handlers.MessageResponse{},
+		}},
+		{http.MethodGet, "/reports/files/:id", "/report/files/:id", openapi.Operation{
+			Summary: "Скачивание отчёта по ID файла", Description: download, ContentType: "application/octet-stream",
+		}},
+		{http.MethodGet, "/reports/schedules", "", openapi.Operation{
+			Summary: "Расписания рассылки отчётов", Response: schedulesResponse{},
+		}},
+		{http.MethodPost, "/reports/schedules", "", openapi.Operation{
+			Summary: "Создание расписания", Body: Schedule{}, Status: http.StatusCreated, Response: Schedule{},
+			Description: "Вместо дат from и to можно указать period: last_day, last_week или last_month — даты считаются при каждом запуске.",
+		}},
+		{http.MethodPut, "/reports/schedules/:id", "", openapi.Operation{
+			Summary: "Изменение расписания", Body: Schedule{}, Response: Schedule{},
+		}},
+		{http.MethodDelete, "/reports/schedules/:id", "", openapi.Operation{
+			Summary: "Удаление расписания", Response: handlers.MessageResponse{},
+		}},
+		{http.MethodGet, "/reports/schedules/:id/runs", "", openapi.Operation{
+			Summary: "История запусков расписания", Response: runsResponse{},
+		}},
+	}
+
+	for _, route := range routes {
+		route.op.Tag = tag
+		route.op.Auth = true
+		route.op.Description = strings.TrimSpace(route.op.Description + " Только для сотрудников.")
+		router.DocV1(route.method, route.path, route.op)
+		if route.legacy != "" {
+			router.Doc(route.method, route.legacy, route.op)
+		}
+	}
+}
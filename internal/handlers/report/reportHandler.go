@@ -2,55 +2,238 @@ package report
 
 import (
 	"AirPort/internal/handlers"
-	"AirPort/package/logs"
-	"fmt"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
+	"context"
 	"net/http"
-	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const jobHistoryLimit = 50
+
 type Handler struct {
-	db *pgxpool.Pool
+	db    *pgxpool.Pool
+	queue *Queue
 }
 
-func NewHandler(db *pgxpool.Pool) handlers.Handlers {
-	return &Handler{db: db}
+func NewHandler(db *pgxpool.Pool, queue *Queue) handlers.Handlers {
+	return &Handler{db: db, queue: queue}
 }
 
-func (h *Handler) RegisterHandler(router *gin.Engine) {
-	router.POST("/report/generateReport", h.GenerateReport)
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.V1.POST("/reports/jobs", auth.RequireEmployee(), h.GenerateReport)
+	router.V1.GET("/reports/jobs", auth.RequireEmployee(), h.GetJobs)
+	router.V1.GET("/reports/jobs/:id", auth.RequireEmployee(), h.GetJob)
+	router.V1.GET("/reports/jobs/:id/download", auth.RequireEmployee(), h.DownloadJob)
+	router.V1.DELETE("/reports/jobs/:id", auth.RequireEmployee(), h.CancelJob)
+	router.V1.GET("/reports/files/:id", auth.RequireEmployee(), h.DownloadReport)
+
+	router.V1.GET("/reports/schedules", auth.RequireEmployee(), h.GetSchedules)
+	router.V1.POST("/reports/schedules", auth.RequireEmployee(), h.CreateSchedule)
+	router.V1.PUT("/reports/schedules/:id", auth.RequireEmployee(), h.UpdateSchedule)
+	router.V1.DELETE("/reports/schedules/:id", auth.RequireEmployee(), h.DeleteSchedule)
+	router.V1.GET("/reports/schedules/:id/runs", auth.RequireEmployee(), h.GetScheduleRuns)
+
+	// Старые маршруты есть только у очереди отчётов: её API появилось до /api/v1
+	router.Deprecated(http.MethodPost, "/report/generateReport", "/reports/jobs", auth.RequireEmployee(), h.GenerateReport)
+	router.Deprecated(http.MethodGet, "/report/jobs", "/reports/jobs", auth.RequireEmployee(), h.GetJobs)
+	router.Deprecated(http.MethodGet, "/report/jobs/:id", "/reports/jobs/:id", auth.RequireEmployee(), h.GetJob)
+	router.Deprecated(http.MethodGet, "/report/jobs/:id/download", "/reports/jobs/:id/download", auth.RequireEmployee(), h.DownloadJob)
+	router.Deprecated(http.MethodDelete, "/report/jobs/:id", "/reports/jobs/:id", auth.RequireEmployee(), h.CancelJob)
+	router.Deprecated(http.MethodGet, "/report/files/:id", "/reports/files/:id", auth.RequireEmployee(), h.DownloadReport)
 }
 
 func (h *Handler) GenerateReport(c *gin.Context) {
 	var report Report
 
-	if err := c.ShouldBindJSON(&report); err != nil {
-		fmt.Println("ошибка чтения данных JSON")
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	if err := handlers.BindJSON(c, &report); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := report.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	job, err := h.queue.Enqueue(c.Request.Context(), report)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Location", handlers.APIPrefix+"/reports/jobs/"+job.Id)
+	c.JSON(http.StatusAccepted, gin.H{"jobId": job.Id, "status": job.Status})
+}
+
+func (h *Handler) GetJobs(c *gin.Context) {
+	var job Job
+
+	jobs, err := database.RetryRead(c.Request.Context(), h.db, func(ctx context.Context, db *pgxpool.Pool) ([]Job, error) {
+		return job.GetHistory(ctx, db, jobHistoryLimit)
+	})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+}
+
+func (h *Handler) GetJob(c *gin.Context) {
+	job := Job{Id: c.Param("id")}
+
+	if err := job.Get(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := report.GetNewReportData(h.db); err != nil {
-		if logErr := logs.NewLog("Отчёт", "report", err); logErr != nil {
-			fmt.Printf("ошибка логирования: %s", logErr)
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *Handler) DownloadJob(c *gin.Context) {
+	job := Job{Id: c.Param("id")}
+
+	if err := job.Get(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	filePath := "./reports/report.pdf"
+	if job.Status != JobDone {
+		c.Error(apperrors.Conflict("отчёт ещё не готов"))
+		return
+	}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		c.JSON(http.StatusNotFound, gin.H{"error": "отчёт не найден"})
+	h.sendReport(c, job.ReportId)
+}
+
+func (h *Handler) CancelJob(c *gin.Context) {
+	err := h.queue.Cancel(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "отменено")})
+}
+
+func (h *Handler) DownloadReport(c *gin.Context) {
+	h.sendReport(c, c.Param("id"))
+}
+
+func (h *Handler) sendReport(c *gin.Context, id string) {
+	path, err := h.queue.store.Path(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if renderer, err := rendererFor(ext); err == nil {
+		c.Header("Content-Type", renderer.ContentType())
+	}
+
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Transfer-Encoding", "binary")
-	c.Header("Content-Disposition", "attachment; filename=report.pdf")
-	c.Header("Content-Type", "application/pdf")
+	c.FileAttachment(path, "report-"+id+"."+ext)
+}
+
+func (h *Handler) GetSchedules(c *gin.Context) {
+	var schedule Schedule
+
+	schedules, err := database.RetryRead(c.Request.Context(), h.db, schedule.GetAll)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+func (h *Handler) CreateSchedule(c *gin.Context) {
+	schedule := Schedule{Enabled: true}
+
+	if err := handlers.BindJSON(c, &schedule); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := schedule.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := schedule.Create(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func (h *Handler) UpdateSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errScheduleNotFound)
+		return
+	}
+
+	var schedule Schedule
+	if err := handlers.BindJSON(c, &schedule); err != nil {
+		c.Error(err)
+		return
+	}
+	schedule.Id = id
+
+	if err := schedule.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := schedule.Update(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+func (h *Handler) DeleteSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errScheduleNotFound)
+		return
+	}
+
+	schedule := Schedule{Id: id}
+	if err := schedule.Delete(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "удалено")})
+}
+
+func (h *Handler) GetScheduleRuns(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.Error(errScheduleNotFound)
+		return
+	}
+
+	schedule := Schedule{Id: id}
+	runs, err := schedule.GetRuns(c.Request.Context(), h.db, jobHistoryLimit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
 
-	c.File(filePath)
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
 }
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"AirPort/package/apperrors"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	JobQueued   = "queued"
+	JobRunning  = "running"
+	JobDone     = "done"
+	JobFailed   = "failed"
+	JobCanceled = "canceled"
+)
+
+var errJobNotFound = apperrors.NotFound("задача не найдена")
+
+type Job struct {
+	Id         string     `db:"id" json:"id"`
+	Params     Report     `db:"params" json:"params"`
+	Status     string     `db:"status" json:"status"`
+	Error      string     `db:"error" json:"error,omitempty"`
+	ReportId   string     `db:"report_id" json:"reportId,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+	StartedAt  *time.Time `db:"started_at" json:"startedAt,omitempty"`
+	FinishedAt *time.Time `db:"finished_at" json:"finishedAt,omitempty"`
+}
+
+func (j *Job) Create(ctx context.Context, db *pgxpool.Pool) error {
+	query := `
+		INSERT INTO Report_Jobs (id, params, status)
+		VALUES ($1, $2, $3)
+		RETURNING created_at
+	`
+
+	if err := db.QueryRow(ctx, query, j.Id, j.Params, JobQueued).Scan(&j.CreatedAt); err != nil {
+		return fmt.Errorf("ошибка при создании задачи отчёта: %w", err)
+	}
+	j.Status = JobQueued
+
+	return nil
+}
+
+func (j *Job) Get(ctx context.Context, db *pgxpool.Pool) error {
+	query := `
+		SELECT id, params, status, error, report_id, created_at, started_at, finished_at
+		FROM Report_Jobs
+		WHERE id = $1
+	`
+
+	err := db.QueryRow(ctx, query, j.Id).Scan(
+		&j.Id,
+		&j.Params,
+		&j.Status,
+		&j.Error,
+		&j.ReportId,
+		&j.CreatedAt,
+		&j.StartedAt,
+		&j.FinishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errJobNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка при получении задачи отчёта: %w", err)
+	}
+
+	return nil
+}
+
+func (j *Job) GetHistory(ctx context.Context, db *pgxpool.Pool, limit int) ([]Job, error) {
+	query := `
+		SELECT id, params, status, error, report_id, created_at, started_at, finished_at
+		FROM Report_Jobs
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+
+	rows, err := db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории отчётов: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	for rows.Next() {
+		var job Job
+		if err := rows.Scan(
+			&job.Id,
+			&job.Params,
+			&job.Status,
+			&job.Error,
+			&job.ReportId,
+			&job.CreatedAt,
+			&job.StartedAt,
+			&job.FinishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка при получении истории отчётов: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории отчётов: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// markRunning забирает задачу в работу, только если её не отменили, пока она стояла в очереди
+func (j *Job) markRunning(ctx context.Context, db *pgxpool.Pool) (bool, error) {
+	query := `
+		UPDATE Report_Jobs
+		SET status = $2, started_at = NOW()
+		WHERE id = $1 AND status = $3
+	`
+
+	tag, err := db.Exec(ctx, query, j.Id, JobRunning, JobQueued)
+	if err != nil {
+		return false, fmt.Errorf("ошибка при запуске задачи отчёта: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// finish записывает итог задачи. Отменённую задачу итог обработчика не перезаписывает
+func (j *Job) finish(ctx context.Context, db *pgxpool.Pool, status, reportId, errText string) error {
+	query := `
+		UPDATE Report_Jobs
+		SET status = $2, report_id = $3, error = $4, finished_at = NOW()
+		WHERE id = $1 AND status <> $5
+	`
+
+	tag, err := db.Exec(ctx, query, j.Id, status, reportId, errText, JobCanceled)
+	if err != nil {
+		return fmt.Errorf("ошибка при завершении задачи отчёта: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		j.Status = status
+	}
+
+	return nil
+}
+
+// cancel отменяет задачу в очереди или в работе; false — задача уже завершена
+func (j *Job) cancel(ctx context.Context, db *pgxpool.Pool) (bool, error) {
+	query := `
+		UPDATE Report_Jobs
+		SET status = $2, error = $3, finished_at = NOW()
+		WHERE id = $1 AND status IN ($4, $5)
+	`
+
+	tag, err := db.Exec(ctx, query, j.Id, JobCanceled, errCanceled.Error(), JobQueued, JobRunning)
+	if err != nil {
+		return false, fmt.Errorf("ошибка при отмене задачи отчёта: %w", err)
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+// failInterrupted помечает задачи, оставшиеся незавершёнными после остановки сервера
+func failInterrupted(ctx context.Context, db *pgxpool.Pool) error {
+	query := `
+		UPDATE Report_Jobs
+		SET status = $1, error = 'прервано перезапуском сервера', finished_at = NOW()
+		WHERE status IN ($2, $3)
+	`
+
+	if _, err := db.Exec(ctx, query, JobFailed, JobQueued, JobRunning); err != nil {
+		return fmt.Errorf("ошибка при очистке прерванных задач: %w", err)
+	}
+
+	return nil
+}
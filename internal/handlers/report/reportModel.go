@@ -1,166 +1,417 @@
 package report
 
 import (
+	"AirPort/package/apperrors"
+	"bytes"
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
-	"github.com/jung-kurt/gofpdf"
 )
 
+const (
+	TypeSales           = "sales"
+	TypeOnTime          = "on_time"
+	TypeLoadFactor      = "load_factor"
+	TypeRouteLoadFactor = "route_load_factor"
+	TypeCancellations   = "cancellations"
+	TypeBusiestHours    = "busiest_hours"
+)
+
+type dataSource func(r *Report, ctx context.Context, db *pgxpool.Pool) (*Dataset, error)
+
+var dataSources = map[string]dataSource{
+	TypeSales:           (*Report).GetSalesData,
+	TypeOnTime:          (*Report).GetOnTimeData,
+	TypeLoadFactor:      (*Report).GetLoadFactorData,
+	TypeRouteLoadFactor: (*Report).GetRouteLoadFactorData,
+	TypeCancellations:   (*Report).GetCancellationData,
+	TypeBusiestHours:    (*Report).GetBusiestHoursData,
+}
+
+const (
+	DefaultFormat          = "pdf"
+	DefaultGranularity     = "month"
+	DefaultMovingAvgWindow = 7
+	MaxMovingAvgWindow     = 366
+
+	DateFormat = "2006-01-02"
+)
+
+// Относительные периоды: даты считаются в момент формирования отчёта
+const (
+	PeriodLastDay   = "last_day"
+	PeriodLastWeek  = "last_week"
+	PeriodLastMonth = "last_month"
+)
+
+var granularities = map[string]bool{
+	"day":     true,
+	"week":    true,
+	"month":   true,
+	"quarter": true,
+	"year":    true,
+}
+
+// legacyIntervals — значения старого поля interval
+var legacyIntervals = map[int]string{
+	1: "week",
+	2: "month",
+	3: "year",
+}
+
 type Report struct {
-	Interval int `json:"interval"`
+	Type string `json:"type,omitempty" binding:"omitempty,oneof=sales on_time load_factor route_load_factor cancellations busiest_hours"`
+	// Interval оставлен для старых клиентов, вместо него используется Granularity
+	Interval    int    `json:"interval,omitempty" binding:"omitempty,oneof=1 2 3"`
+	Format      string `json:"format,omitempty" binding:"omitempty,oneof=pdf csv xlsx json"`
+	From        string `json:"from,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	To          string `json:"to,omitempty" binding:"omitempty,datetime=2006-01-02"`
+	Granularity string `json:"granularity,omitempty" binding:"omitempty,oneof=day week month quarter year"`
+	// Period — вчерашний день, прошлая неделя или прошлый месяц вместо from и to
+	Period string `json:"period,omitempty" binding:"omitempty,oneof=last_day last_week last_month"`
+
+	Route        string `json:"route,omitempty" binding:"max=255"`
+	Airline      string `json:"airline,omitempty" binding:"omitempty,airline"`
+	FareClass    string `json:"fareClass,omitempty" binding:"omitempty,oneof=economy business first"`
+	FlightNumber string `json:"flightNumber,omitempty" binding:"omitempty,flightnumber"`
+
+	// MovingAvgWindow — сколько предыдущих периодов входит в скользящее среднее
+	MovingAvgWindow *int `json:"movingAvgWindow,omitempty" binding:"omitempty,min=0,max=366"`
 }
 
-func (r *Report) GetNewReportData(db *pgxpool.Pool) error {
-	ctx := context.Background()
+// Row — типизированная строка отчёта; Cells отдаёт значения колонок в порядке Dataset.Columns
+type Row interface {
+	Cells() []any
+}
 
-	selectedIntervalOption := ""
+type Param struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
 
-	switch r.Interval {
-	case 1:
-		selectedIntervalOption = "week"
-	case 2:
-		selectedIntervalOption = "month"
-	case 3:
-		selectedIntervalOption = "year"
-	default:
-		return fmt.Errorf("неподдерживаемый интвервал")
+type Dataset struct {
+	Title       string
+	Columns     []string
+	Rows        []Row
+	Params      []Param
+	Charts      []Chart
+	GeneratedAt time.Time
+}
+
+type SalesRow struct {
+	Period      time.Time `json:"period"`
+	TicketsSold int       `json:"ticketsSold"`
+	Revenue     float64   `json:"revenue"`
+	AvgPrice    float64   `json:"avgPrice"`
+	MovingAvg   float64   `json:"movingAvg"`
+}
+
+func (s SalesRow) Cells() []any {
+	return []any{s.Period.Format("2006-01-02"), s.TicketsSold, s.Revenue, s.AvgPrice, s.MovingAvg}
+}
+
+type File struct {
+	Data        []byte
+	Extension   string
+	ContentType string
+}
+
+// GetNewReportData собирает данные и возвращает готовый файл в выбранном формате
+func (r *Report) GetNewReportData(ctx context.Context, db *pgxpool.Pool) (*File, error) {
+	renderer, err := rendererFor(r.format())
+	if err != nil {
+		return nil, err
 	}
 
-	query := `
-		SELECT 
-			DATE_TRUNC($1, b.departure) AS period,
-			COUNT(t.id) AS tickets_sold,
-			SUM(t.price)::FLOAT AS daily_revenue,
-			AVG(t.price)::FLOAT AS avg_price,
-			AVG(COUNT(t.id)) OVER (
-				ORDER BY DATE_TRUNC($1, b.departure) 
-				ROWS BETWEEN 7 PRECEDING AND CURRENT ROW
-			)::FLOAT AS moving_avg
-		FROM 
-			Board b
-		JOIN 
-			Tickets t ON b.id = t.flightId
-		GROUP BY 
-			period
-		ORDER BY 
-			period;
-	`
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
 
-	rows, err := db.Query(ctx, query, selectedIntervalOption)
+	now := time.Now()
+	report := r.resolvePeriod(now)
+
+	data, err := dataSources[report.reportType()](&report, ctx, db)
 	if err != nil {
-		return fmt.Errorf("ошибка получения данных из бд для отчёта")
+		return nil, err
 	}
+	data.Params = report.params()
+	data.GeneratedAt = now
 
-	defer rows.Close()
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, data); err != nil {
+		return nil, fmt.Errorf("ошибка при создании файла отчёта: %w", err)
+	}
 
-	var results []string
+	return &File{Data: buf.Bytes(), Extension: renderer.Extension(), ContentType: renderer.ContentType()}, nil
+}
 
-	results = append(results, "Period, Tickets sold, Daily revenue, Average price, Average price per move")
+func (r *Report) format() string {
+	if r.Format == "" {
+		return DefaultFormat
+	}
 
-	for rows.Next() {
-		var (
-			period       time.Time
-			ticketsSold  int
-			dailyRevenue float64
-			avgPrice     float64
-			movingAvg    float64
-		)
+	return r.Format
+}
 
-		err := rows.Scan(
-			&period,
-			&ticketsSold,
-			&dailyRevenue,
-			&avgPrice,
-			&movingAvg,
-		)
-		if err != nil {
-			return fmt.Errorf("ошибка чтения данных: %w", err)
+func (r *Report) reportType() string {
+	if r.Type == "" {
+		return TypeSales
+	}
+
+	return r.Type
+}
+
+func (r *Report) granularity() string {
+	if r.Granularity != "" {
+		return r.Granularity
+	}
+	if legacy, ok := legacyIntervals[r.Interval]; ok {
+		return legacy
+	}
+
+	return DefaultGranularity
+}
+
+func (r *Report) movingAvgWindow() int {
+	if r.MovingAvgWindow == nil {
+		return DefaultMovingAvgWindow
+	}
+
+	return *r.MovingAvgWindow
+}
+
+// Validate проверяет параметры до постановки отчёта в очередь
+func (r *Report) Validate() error {
+	if _, ok := dataSources[r.reportType()]; !ok {
+		return apperrors.Field("type", "неизвестный тип отчёта: %s", r.Type)
+	}
+	if _, err := rendererFor(r.format()); err != nil {
+		return err
+	}
+	// Класс обслуживания есть только у билетов, остальные отчёты считают рейсы целиком
+	if r.FareClass != "" && r.reportType() != TypeSales {
+		return apperrors.Field("fareClass", "фильтр доступен только для отчёта о продажах")
+	}
+
+	if r.Granularity == "" && r.Interval != 0 {
+		if _, ok := legacyIntervals[r.Interval]; !ok {
+			return apperrors.Field("interval", "неподдерживаемый интервал")
 		}
+	}
+	if !granularities[r.granularity()] {
+		return apperrors.Field("granularity", "неподдерживаемая группировка: %s", r.Granularity)
+	}
 
-		rowStr := fmt.Sprintf(
-			"%s,%d,%.2f,%.2f,%.2f",
-			period.Format("2006-01-02"),
-			ticketsSold,
-			dailyRevenue,
-			avgPrice,
-			movingAvg,
-		)
-		results = append(results, rowStr)
+	if window := r.movingAvgWindow(); window < 0 || window > MaxMovingAvgWindow {
+		return apperrors.Field("movingAvgWindow", "значение должно быть от 0 до %d", MaxMovingAvgWindow)
 	}
 
-	if err = rows.Err(); err != nil {
-		return fmt.Errorf("ошибка при обработке результатов: %w", err)
+	if r.Period != "" && (r.From != "" || r.To != "") {
+		return apperrors.Field("period", "нельзя указывать вместе с from и to")
+	}
+	if _, _, ok := periodRange(r.Period, time.Now()); r.Period != "" && !ok {
+		return apperrors.Field("period", "неизвестный период: %s", r.Period)
 	}
 
-	if err := r.generateNewReport(results); err != nil {
-		return fmt.Errorf("ошибка при создании pdf файла: %s", err)
+	from, to, err := r.dateRange()
+	if err != nil {
+		return err
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return apperrors.Field("to", "дата окончания раньше даты начала")
 	}
 
 	return nil
 }
 
-func (r *Report) generateNewReport(reportData []string) error {
-	if err := os.MkdirAll("./reports", os.ModePerm); err != nil {
-		return fmt.Errorf("ошибка при создании директории: %w", err)
+// resolvePeriod возвращает копию параметров, где относительный период заменён датами
+func (r *Report) resolvePeriod(now time.Time) Report {
+	report := *r
+	if from, to, ok := periodRange(r.Period, now); ok {
+		report.From, report.To = from.Format(DateFormat), to.Format(DateFormat)
+	}
+
+	return report
+}
+
+// periodRange считает первый и последний день периода, закончившегося до now.
+// Неделя начинается с понедельника
+func periodRange(period string, now time.Time) (from, to time.Time, ok bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch period {
+	case PeriodLastDay:
+		from = today.AddDate(0, 0, -1)
+		return from, from, true
+	case PeriodLastWeek:
+		monday := today.AddDate(0, 0, -(int(today.Weekday())+6)%7)
+		return monday.AddDate(0, 0, -7), monday.AddDate(0, 0, -1), true
+	case PeriodLastMonth:
+		first := today.AddDate(0, 0, 1-today.Day())
+		return first.AddDate(0, -1, 0), first.AddDate(0, 0, -1), true
+	}
+
+	return from, to, false
+}
+
+func (r *Report) dateRange() (from, to time.Time, err error) {
+	if r.From != "" {
+		if from, err = time.Parse(DateFormat, r.From); err != nil {
+			return from, to, apperrors.Field("from", "неверный формат, ожидается %s", DateFormat)
+		}
+	}
+	if r.To != "" {
+		if to, err = time.Parse(DateFormat, r.To); err != nil {
+			return from, to, apperrors.Field("to", "неверный формат, ожидается %s", DateFormat)
+		}
+	}
+
+	return from, to, nil
+}
+
+// filters собирает условия WHERE; номера параметров продолжают уже занятые в args
+func (r *Report) filters(args []any) ([]string, []any, error) {
+	from, to, err := r.dateRange()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conditions []string
+	add := func(condition string, value any) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !from.IsZero() {
+		add("b.departure >= $%d", from)
+	}
+	if !to.IsZero() {
+		// Дата окончания входит в период целиком
+		add("b.departure < $%d", to.AddDate(0, 0, 1))
+	}
+	if r.Route != "" {
+		add("b.appointment = $%d", r.Route)
+	}
+	if r.Airline != "" {
+		add("LEFT(b.flightNumber, 2) = $%d", strings.ToUpper(r.Airline))
+	}
+	if r.FareClass != "" {
+		add("t.fareClass = $%d", r.FareClass)
+	}
+	if r.FlightNumber != "" {
+		add("b.flightNumber = $%d", strings.ToUpper(r.FlightNumber))
 	}
 
-	filename := filepath.Join("./reports/report.pdf")
+	return conditions, args, nil
+}
+
+// params описывает параметры отчёта для шапки файла
+func (r *Report) params() []Param {
+	params := []Param{
+		{Name: "Отчёт", Value: r.reportType()},
+		{Name: "Группировка", Value: r.granularity()},
+	}
 
-	if _, err := os.Stat(filename); err == nil {
-		if err := os.Remove(filename); err != nil {
-			return fmt.Errorf("ошибка при удалении существующего файла: %w", err)
+	optional := []Param{
+		{Name: "Период", Value: r.Period},
+		{Name: "С", Value: r.From},
+		{Name: "По", Value: r.To},
+		{Name: "Направление", Value: r.Route},
+		{Name: "Авиакомпания", Value: r.Airline},
+		{Name: "Класс", Value: r.FareClass},
+		{Name: "Рейс", Value: r.FlightNumber},
+	}
+	for _, param := range optional {
+		if param.Value != "" {
+			params = append(params, param)
 		}
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("ошибка при проверке существования файла: %w", err)
 	}
 
-	pdf := gofpdf.New("P", "mm", "A4", "")
+	if r.reportType() == TypeSales {
+		params = append(params, Param{Name: "Скользящее среднее", Value: fmt.Sprintf("%d", r.movingAvgWindow())})
+	}
 
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
+	return params
+}
 
-	title := "Ticket Sales Report"
-	switch r.Interval {
-	case 1:
-		title += " (week)"
-	case 2:
-		title += " (month)"
-	case 3:
-		title += " (year)"
+func (r *Report) title(name string) string {
+	title := fmt.Sprintf("%s (%s)", name, r.granularity())
+	if r.From != "" || r.To != "" {
+		title += fmt.Sprintf(" %s - %s", r.From, r.To)
 	}
-	pdf.Cell(40, 10, title)
-	pdf.Ln(12)
 
-	pdf.SetFont("Arial", "", 10)
-	colWidths := []float64{40, 30, 30, 30, 40}
+	return title
+}
+
+func (r *Report) GetSalesData(ctx context.Context, db *pgxpool.Pool) (*Dataset, error) {
+	conditions, args, err := r.filters([]any{r.granularity()})
+	if err != nil {
+		return nil, err
+	}
+
+	// Границу окна нельзя передать параметром, поэтому подставляем проверенное число
+	query := fmt.Sprintf(`
+		SELECT 
+			DATE_TRUNC($1, b.departure) AS period,
+			COUNT(t.id) AS tickets_sold,
+			SUM(t.price)::FLOAT AS daily_revenue,
+			AVG(t.price)::FLOAT AS avg_price,
+			AVG(COUNT(t.id)) OVER (
+				ORDER BY DATE_TRUNC($1, b.departure) 
+				ROWS BETWEEN %d PRECEDING AND CURRENT ROW
+			)::FLOAT AS moving_avg
+		FROM 
+			Board b
+		JOIN 
+			Tickets t ON b.id = t.flightId
+		%s
+		GROUP BY 
+			period
+		ORDER BY 
+			period;
+	`, r.movingAvgWindow(), whereClause(conditions...))
 
-	if len(reportData) == 0 {
-		return fmt.Errorf("нет данных для отчета")
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения данных из бд для отчёта: %w", err)
 	}
 
-	headers := strings.Split(reportData[0], ",")
-	for i, header := range headers {
-		pdf.CellFormat(colWidths[i], 7, header, "1", 0, "C", false, 0, "")
+	defer rows.Close()
+
+	data := &Dataset{
+		Title:   r.title("Ticket Sales Report"),
+		Columns: []string{"Period", "Tickets sold", "Daily revenue", "Average price", "Average price per move"},
 	}
-	pdf.Ln(-1)
 
-	for _, row := range reportData[1:] {
-		columns := strings.Split(row, ",")
-		for i, col := range columns {
-			pdf.CellFormat(colWidths[i], 6, col, "1", 0, "C", false, 0, "")
+	for rows.Next() {
+		var row SalesRow
+
+		err := rows.Scan(
+			&row.Period,
+			&row.TicketsSold,
+			&row.Revenue,
+			&row.AvgPrice,
+			&row.MovingAvg,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
 		}
-		pdf.Ln(-1)
+
+		data.Rows = append(data.Rows, row)
 	}
 
-	if err := pdf.OutputFileAndClose(filename); err != nil {
-		return fmt.Errorf("ошибка сохранения PDF: %w", err)
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
 	}
 
-	return nil
+	period := func(s SalesRow) string { return s.Period.Format(DateFormat) }
+	data.Charts = []Chart{
+		newChart(data.Title+": revenue", ChartLine, data.Rows, period, func(s SalesRow) float64 { return s.Revenue }),
+		newChart(data.Title+": tickets sold", ChartBar, data.Rows, period, func(s SalesRow) float64 { return float64(s.TicketsSold) }),
+	}
+
+	return data, nil
 }
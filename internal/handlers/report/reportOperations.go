@@ -0,0 +1,388 @@
+package report
+
+import (
+	"AirPort/internal/handlers/board"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// OnTimeThreshold — задержка в минутах, до которой вылет считается вовремя
+const OnTimeThreshold = 15
+
+type OnTimeRow struct {
+	Period        time.Time `json:"period"`
+	Flights       int       `json:"flights"`
+	OnTimePercent float64   `json:"onTimePercent"`
+	AvgDelay      float64   `json:"avgDelayMinutes"`
+	MedianDelay   float64   `json:"medianDelayMinutes"`
+	P90Delay      float64   `json:"p90DelayMinutes"`
+	Delayed15To60 int       `json:"delayed15To60"`
+	DelayedOver60 int       `json:"delayedOver60"`
+}
+
+func (o OnTimeRow) Cells() []any {
+	return []any{
+		o.Period.Format(DateFormat), o.Flights, o.OnTimePercent, o.AvgDelay,
+		o.MedianDelay, o.P90Delay, o.Delayed15To60, o.DelayedOver60,
+	}
+}
+
+type LoadFactorRow struct {
+	FlightNumber string    `json:"flightNumber"`
+	Route        string    `json:"route"`
+	Departure    time.Time `json:"departure"`
+	Seats        int       `json:"seats"`
+	TicketsSold  int       `json:"ticketsSold"`
+	LoadFactor   float64   `json:"loadFactor"`
+}
+
+func (l LoadFactorRow) Cells() []any {
+	return []any{l.FlightNumber, l.Route, l.Departure.Format(board.TimeFormat), l.Seats, l.TicketsSold, l.LoadFactor}
+}
+
+type RouteLoadFactorRow struct {
+	Route       string  `json:"route"`
+	Flights     int     `json:"flights"`
+	Seats       int     `json:"seats"`
+	TicketsSold int     `json:"ticketsSold"`
+	LoadFactor  float64 `json:"loadFactor"`
+}
+
+func (l RouteLoadFactorRow) Cells() []any {
+	return []any{l.Route, l.Flights, l.Seats, l.TicketsSold, l.LoadFactor}
+}
+
+type CancellationRow struct {
+	Period           time.Time `json:"period"`
+	Flights          int       `json:"flights"`
+	Canceled         int       `json:"canceled"`
+	CancellationRate float64   `json:"cancellationRate"`
+}
+
+func (c CancellationRow) Cells() []any {
+	return []any{c.Period.Format(DateFormat), c.Flights, c.Canceled, c.CancellationRate}
+}
+
+type BusiestHourRow struct {
+	Hour        int `json:"hour"`
+	Flights     int `json:"flights"`
+	TicketsSold int `json:"ticketsSold"`
+}
+
+func (b BusiestHourRow) Cells() []any {
+	return []any{fmt.Sprintf("%02d:00", b.Hour), b.Flights, b.TicketsSold}
+}
+
+// whereClause объединяет условия фильтров с дополнительными условиями отчёта
+func whereClause(conditions ...string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND ")
+}
+
+func (r *Report) GetOnTimeData(ctx context.Context, db *pgxpool.Pool) (*Dataset, error) {
+	conditions, args, err := r.filters([]any{r.granularity(), board.StatusDeparted})
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, "(b.actual_departure IS NOT NULL OR b.status = $2)")
+
+	query := fmt.Sprintf(`
+		WITH delays AS (
+			SELECT
+				b.departure,
+				EXTRACT(EPOCH FROM (
+					COALESCE(b.actual_departure, b.status_change_time) - b.departure
+				)) / 60 AS delay
+			FROM Board b
+			%s
+		)
+		SELECT
+			DATE_TRUNC($1, departure) AS period,
+			COUNT(*),
+			(COUNT(*) FILTER (WHERE delay <= %d) * 100.0 / COUNT(*))::FLOAT,
+			AVG(delay)::FLOAT,
+			PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY delay)::FLOAT,
+			PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY delay)::FLOAT,
+			COUNT(*) FILTER (WHERE delay > %d AND delay <= 60),
+			COUNT(*) FILTER (WHERE delay > 60)
+		FROM delays
+		GROUP BY period
+		ORDER BY period
+	`, whereClause(conditions...), OnTimeThreshold, OnTimeThreshold)
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения данных о пунктуальности: %w", err)
+	}
+	defer rows.Close()
+
+	data := &Dataset{
+		Title: r.title("On-time Performance"),
+		Columns: []string{
+			"Period", "Flights", "On time, %", "Avg delay, min",
+			"Median delay, min", "P90 delay, min", "Delayed 15-60", "Delayed 60+",
+		},
+	}
+
+	for rows.Next() {
+		var row OnTimeRow
+		if err := rows.Scan(
+			&row.Period,
+			&row.Flights,
+			&row.OnTimePercent,
+			&row.AvgDelay,
+			&row.MedianDelay,
+			&row.P90Delay,
+			&row.Delayed15To60,
+			&row.DelayedOver60,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	data.Charts = []Chart{
+		newChart("On time, %", ChartBar, data.Rows,
+			func(o OnTimeRow) string { return o.Period.Format(DateFormat) },
+			func(o OnTimeRow) float64 { return o.OnTimePercent }),
+		newChart("Average delay, min", ChartLine, data.Rows,
+			func(o OnTimeRow) string { return o.Period.Format(DateFormat) },
+			func(o OnTimeRow) float64 { return o.AvgDelay }),
+	}
+
+	return data, nil
+}
+
+func (r *Report) GetLoadFactorData(ctx context.Context, db *pgxpool.Pool) (*Dataset, error) {
+	conditions, args, err := r.filters(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			b.flightNumber,
+			b.appointment,
+			b.departure,
+			b.seat_capacity,
+			COUNT(t.id),
+			(COUNT(t.id) * 100.0 / NULLIF(b.seat_capacity, 0))::FLOAT
+		FROM Board b
+		LEFT JOIN Tickets t ON t.flightId = b.id
+		%s
+		GROUP BY b.id
+		ORDER BY b.departure
+	`, whereClause(conditions...))
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения данных о загрузке рейсов: %w", err)
+	}
+	defer rows.Close()
+
+	data := &Dataset{
+		Title:   r.title("Load Factor by Flight"),
+		Columns: []string{"Flight", "Route", "Departure", "Seats", "Tickets sold", "Load factor, %"},
+	}
+
+	for rows.Next() {
+		var row LoadFactorRow
+		if err := rows.Scan(
+			&row.FlightNumber,
+			&row.Route,
+			&row.Departure,
+			&row.Seats,
+			&row.TicketsSold,
+			&row.LoadFactor,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	return data, nil
+}
+
+func (r *Report) GetRouteLoadFactorData(ctx context.Context, db *pgxpool.Pool) (*Dataset, error) {
+	conditions, args, err := r.filters(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		WITH flights AS (
+			SELECT b.id, b.appointment, b.seat_capacity, COUNT(t.id) AS sold
+			FROM Board b
+			LEFT JOIN Tickets t ON t.flightId = b.id
+			%s
+			GROUP BY b.id
+		)
+		SELECT
+			appointment,
+			COUNT(*),
+			SUM(seat_capacity),
+			SUM(sold),
+			(SUM(sold) * 100.0 / NULLIF(SUM(seat_capacity), 0))::FLOAT AS load_factor
+		FROM flights
+		GROUP BY appointment
+		ORDER BY load_factor DESC NULLS LAST
+	`, whereClause(conditions...))
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения данных о загрузке направлений: %w", err)
+	}
+	defer rows.Close()
+
+	data := &Dataset{
+		Title:   r.title("Load Factor by Route"),
+		Columns: []string{"Route", "Flights", "Seats", "Tickets sold", "Load factor, %"},
+	}
+
+	for rows.Next() {
+		var (
+			row        RouteLoadFactorRow
+			loadFactor *float64
+		)
+		if err := rows.Scan(
+			&row.Route,
+			&row.Flights,
+			&row.Seats,
+			&row.TicketsSold,
+			&loadFactor,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		if loadFactor != nil {
+			row.LoadFactor = *loadFactor
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	data.Charts = []Chart{
+		newChart("Load factor by route, %", ChartBar, data.Rows,
+			func(l RouteLoadFactorRow) string { return l.Route },
+			func(l RouteLoadFactorRow) float64 { return l.LoadFactor }),
+	}
+
+	return data, nil
+}
+
+func (r *Report) GetCancellationData(ctx context.Context, db *pgxpool.Pool) (*Dataset, error) {
+	conditions, args, err := r.filters([]any{r.granularity(), board.StatusCanceled})
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			DATE_TRUNC($1, b.departure) AS period,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE b.status = $2),
+			(COUNT(*) FILTER (WHERE b.status = $2) * 100.0 / COUNT(*))::FLOAT
+		FROM Board b
+		%s
+		GROUP BY period
+		ORDER BY period
+	`, whereClause(conditions...))
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения данных об отменах: %w", err)
+	}
+	defer rows.Close()
+
+	data := &Dataset{
+		Title:   r.title("Cancellations"),
+		Columns: []string{"Period", "Flights", "Canceled", "Cancellation rate, %"},
+	}
+
+	for rows.Next() {
+		var row CancellationRow
+		if err := rows.Scan(&row.Period, &row.Flights, &row.Canceled, &row.CancellationRate); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	data.Charts = []Chart{
+		newChart("Cancellation rate, %", ChartBar, data.Rows,
+			func(c CancellationRow) string { return c.Period.Format(DateFormat) },
+			func(c CancellationRow) float64 { return c.CancellationRate }),
+	}
+
+	return data, nil
+}
+
+func (r *Report) GetBusiestHoursData(ctx context.Context, db *pgxpool.Pool) (*Dataset, error) {
+	conditions, args, err := r.filters(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			EXTRACT(HOUR FROM b.departure)::INT AS hour,
+			COUNT(DISTINCT b.id),
+			COUNT(t.id)
+		FROM Board b
+		LEFT JOIN Tickets t ON t.flightId = b.id
+		%s
+		GROUP BY hour
+		ORDER BY hour
+	`, whereClause(conditions...))
+
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения данных о загрузке по часам: %w", err)
+	}
+	defer rows.Close()
+
+	data := &Dataset{
+		Title:   "Busiest Hours",
+		Columns: []string{"Hour", "Flights", "Tickets sold"},
+	}
+
+	for rows.Next() {
+		var row BusiestHourRow
+		if err := rows.Scan(&row.Hour, &row.Flights, &row.TicketsSold); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных: %w", err)
+		}
+		data.Rows = append(data.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при обработке результатов: %w", err)
+	}
+
+	data.Charts = []Chart{
+		newChart("Flights by hour", ChartBar, data.Rows,
+			func(b BusiestHourRow) string { return fmt.Sprintf("%02d", b.Hour) },
+			func(b BusiestHourRow) float64 { return float64(b.Flights) }),
+	}
+
+	return data, nil
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+//go:embed fonts/DejaVuSans.ttf
+var fontRegular []byte
+
+//go:embed fonts/DejaVuSans-Bold.ttf
+var fontBold []byte
+
+const (
+	fontFamily  = "DejaVu"
+	brandName   = "AirPort"
+	pageMargin  = 10.0
+	headerTop   = 20.0
+	footerSpace = 15.0
+	chartHeight = 70.0
+
+	generatedAtFormat = "2006-01-02 15:04:05"
+)
+
+var brandColor = [3]int{0, 82, 147}
+
+type pdfRenderer struct{}
+
+func (pdfRenderer) ContentType() string { return "application/pdf" }
+func (pdfRenderer) Extension() string   { return "pdf" }
+
+func (pdfRenderer) Render(w io.Writer, data *Dataset) error {
+	// Широкие таблицы не помещаются на книжную страницу
+	orientation := "P"
+	if len(data.Columns) > 6 {
+		orientation = "L"
+	}
+
+	pdf := gofpdf.New(orientation, "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes(fontFamily, "", fontRegular)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", fontBold)
+	pdf.SetMargins(pageMargin, headerTop, pageMargin)
+	pdf.SetAutoPageBreak(true, footerSpace)
+	pdf.AliasNbPages("")
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*pageMargin
+
+	pdf.SetHeaderFunc(func() {
+		pdf.SetXY(pageMargin, 8)
+		pdf.SetFont(fontFamily, "B", 12)
+		pdf.SetTextColor(brandColor[0], brandColor[1], brandColor[2])
+		pdf.CellFormat(contentWidth/2, 6, brandName, "", 0, "L", false, 0, "")
+
+		pdf.SetFont(fontFamily, "", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(contentWidth/2, 6, data.Title, "", 0, "R", false, 0, "")
+
+		pdf.SetDrawColor(brandColor[0], brandColor[1], brandColor[2])
+		pdf.SetLineWidth(0.4)
+		pdf.Line(pageMargin, 15, pageWidth-pageMargin, 15)
+
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.SetLineWidth(0.2)
+		pdf.SetY(headerTop)
+	})
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont(fontFamily, "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(contentWidth/2, 5, "Сформировано: "+data.GeneratedAt.Format(generatedAtFormat), "", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth/2, 5, fmt.Sprintf("Страница %d из {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	pdf.AddPage()
+
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(contentWidth, 10, data.Title, "", 1, "L", false, 0, "")
+
+	if len(data.Params) > 0 {
+		pdf.SetFont(fontFamily, "", 9)
+		for _, param := range data.Params {
+			pdf.CellFormat(40, 5, param.Name+":", "", 0, "L", false, 0, "")
+			pdf.CellFormat(contentWidth-40, 5, param.Value, "", 1, "L", false, 0, "")
+		}
+		pdf.Ln(4)
+	}
+
+	for _, chart := range data.Charts {
+		if pdf.GetY()+chartHeight > pageHeight-footerSpace {
+			pdf.AddPage()
+		}
+		y := pdf.GetY()
+		drawChart(pdf, chart, pageMargin, y, contentWidth, chartHeight)
+		pdf.SetY(y + chartHeight + 6)
+	}
+
+	drawTable(pdf, data, contentWidth, pageHeight)
+
+	return pdf.Output(w)
+}
+
+// drawTable выводит таблицу и повторяет шапку на каждой новой странице
+func drawTable(pdf *gofpdf.Fpdf, data *Dataset, contentWidth, pageHeight float64) {
+	const (
+		headerHeight = 8.0
+		rowHeight    = 6.0
+	)
+
+	colWidth := contentWidth / float64(len(data.Columns))
+
+	header := func() {
+		pdf.SetFont(fontFamily, "B", 9)
+		pdf.SetFillColor(230, 236, 243)
+		for _, column := range data.Columns {
+			pdf.CellFormat(colWidth, headerHeight, column, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(fontFamily, "", 9)
+	}
+
+	if pdf.GetY()+headerHeight+rowHeight > pageHeight-footerSpace {
+		pdf.AddPage()
+	}
+	header()
+
+	for _, row := range data.Rows {
+		if pdf.GetY()+rowHeight > pageHeight-footerSpace {
+			pdf.AddPage()
+			header()
+		}
+		for _, cell := range row.Cells() {
+			pdf.CellFormat(colWidth, rowHeight, formatCell(cell), "1", 0, "C", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
@@ -0,0 +1,212 @@
+package report
+
+import (
+	"AirPort/package/apperrors"
+	"AirPort/package/database"
+	"AirPort/package/logs"
+	"AirPort/package/metrics"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+var (
+	errQueueFull    = apperrors.Unavailable("очередь отчётов переполнена, повторите позже")
+	errJobFinished  = apperrors.Conflict("задача уже завершена")
+	errQueueStopped = errors.New("обработчики отчётов не запущены")
+	errCanceled     = errors.New("задача отменена")
+	errShutdown     = errors.New("прервано остановкой сервера")
+)
+
+// Queue выполняет задачи отчётов ограниченным числом обработчиков
+type Queue struct {
+	db         *pgxpool.Pool
+	store      *Store
+	workers    int
+	jobTimeout time.Duration
+
+	jobs    chan string
+	running atomic.Int32
+	wg      sync.WaitGroup
+	stop    context.CancelCauseFunc
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func NewQueue(db *pgxpool.Pool, store *Store, workers, size int, jobTimeout time.Duration) *Queue {
+	return &Queue{
+		db:         db,
+		store:      store,
+		workers:    workers,
+		jobTimeout: jobTimeout,
+		jobs:       make(chan string, size),
+		cancels:    make(map[string]context.CancelFunc),
+	}
+}
+
+func (q *Queue) Start(ctx context.Context) error {
+	if err := failInterrupted(ctx, q.db); err != nil {
+		return err
+	}
+
+	ctx, q.stop = context.WithCancelCause(context.Background())
+	for i := 0; i < q.workers; i++ {
+		q.wg.Add(1)
+		go q.work(ctx)
+	}
+
+	return nil
+}
+
+// Stop прерывает выполняемые задачи и ждёт завершения обработчиков.
+// Прерванные задачи завершаются ошибкой, а не считаются отменёнными пользователем
+func (q *Queue) Stop() {
+	if q.stop == nil {
+		return
+	}
+	q.stop(errShutdown)
+	q.wg.Wait()
+}
+
+// Check используется проверкой готовности
+func (q *Queue) Check(ctx context.Context) error {
+	if int(q.running.Load()) != q.workers {
+		return errQueueStopped
+	}
+
+	return nil
+}
+
+func (q *Queue) Enqueue(ctx context.Context, params Report) (*Job, error) {
+	id, err := newReportID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{Id: id, Params: params}
+	if err := job.Create(ctx, q.db); err != nil {
+		return nil, err
+	}
+
+	select {
+	case q.jobs <- job.Id:
+		return job, nil
+	default:
+		if err := job.finish(ctx, q.db, JobFailed, "", errQueueFull.Error()); err != nil {
+			return nil, err
+		}
+		return nil, errQueueFull
+	}
+}
+
+func (q *Queue) Cancel(ctx context.Context, id string) error {
+	job := &Job{Id: id}
+	canceled, err := job.cancel(ctx, q.db)
+	if err != nil {
+		return err
+	}
+
+	// Выполняемую задачу ещё нужно остановить
+	q.mu.Lock()
+	cancel, ok := q.cancels[id]
+	q.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	if canceled {
+		return nil
+	}
+
+	if err := job.Get(ctx, q.db); err != nil {
+		return err
+	}
+
+	return errJobFinished
+}
+
+func (q *Queue) work(ctx context.Context) {
+	defer q.wg.Done()
+
+	q.running.Add(1)
+	defer q.running.Add(-1)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-q.jobs:
+			q.process(ctx, id)
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, id string) {
+	job := &Job{Id: id}
+	ctx = logs.WithAttrs(ctx, "report_job", id)
+
+	if err := job.Get(ctx, q.db); err != nil {
+		logs.NewLog(ctx, "Задача отчёта", "report", err)
+		return
+	}
+
+	// Отмена регистрируется до перевода в running, чтобы Cancel застал её в любой момент
+	jobCtx, cancel := context.WithTimeout(ctx, q.jobTimeout)
+	q.mu.Lock()
+	q.cancels[id] = cancel
+	q.mu.Unlock()
+
+	defer func() {
+		q.mu.Lock()
+		delete(q.cancels, id)
+		q.mu.Unlock()
+		cancel()
+	}()
+
+	started, err := job.markRunning(ctx, q.db)
+	if err != nil {
+		logs.NewLog(ctx, "Задача отчёта", "report", err)
+		return
+	}
+	if !started {
+		return
+	}
+
+	status, reportId, errText := q.run(jobCtx, job)
+
+	// Итог записываем даже после отмены контекста задачи
+	if err := job.finish(context.WithoutCancel(ctx), q.db, status, reportId, errText); err != nil {
+		logs.NewLog(ctx, "Задача отчёта", "report", err)
+		return
+	}
+
+	if status == JobDone {
+		metrics.ReportsGenerated.WithLabelValues(job.Params.reportType(), job.Params.format()).Inc()
+	}
+}
+
+func (q *Queue) run(ctx context.Context, job *Job) (status, reportId, errText string) {
+	file, err := database.RetryRead(ctx, q.db, job.Params.GetNewReportData)
+	if err == nil {
+		reportId, err = q.store.Save(file)
+	}
+
+	switch {
+	case err == nil:
+		return JobDone, reportId, ""
+	case errors.Is(context.Cause(ctx), errShutdown):
+		return JobFailed, "", errShutdown.Error()
+	case errors.Is(ctx.Err(), context.Canceled):
+		return JobCanceled, "", errCanceled.Error()
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return JobFailed, "", fmt.Sprintf("превышено время формирования отчёта (%s)", q.jobTimeout)
+	default:
+		logs.NewLog(ctx, "Задача отчёта", "report", err)
+		return JobFailed, "", err.Error()
+	}
+}
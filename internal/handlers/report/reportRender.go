@@ -0,0 +1,141 @@
+package report
+
+import (
+	"AirPort/package/apperrors"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Renderer превращает данные отчёта в файл; все форматы строятся из одного Dataset
+type Renderer interface {
+	ContentType() string
+	Extension() string
+	Render(w io.Writer, data *Dataset) error
+}
+
+var renderers = map[string]Renderer{
+	"pdf":  pdfRenderer{},
+	"csv":  csvRenderer{},
+	"xlsx": xlsxRenderer{},
+	"json": jsonRenderer{},
+}
+
+func rendererFor(format string) (Renderer, error) {
+	renderer, ok := renderers[format]
+	if !ok {
+		return nil, apperrors.Validation("неподдерживаемый формат отчёта: %s", format)
+	}
+
+	return renderer, nil
+}
+
+// formatCell приводит значение ячейки к строке для текстовых форматов
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+type csvRenderer struct{}
+
+func (csvRenderer) ContentType() string { return "text/csv; charset=utf-8" }
+func (csvRenderer) Extension() string   { return "csv" }
+
+func (csvRenderer) Render(w io.Writer, data *Dataset) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(data.Columns); err != nil {
+		return err
+	}
+
+	for _, row := range data.Rows {
+		cells := row.Cells()
+		record := make([]string, len(cells))
+		for i, cell := range cells {
+			record[i] = formatCell(cell)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+type xlsxRenderer struct{}
+
+func (xlsxRenderer) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+func (xlsxRenderer) Extension() string { return "xlsx" }
+
+func (xlsxRenderer) Render(w io.Writer, data *Dataset) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	const sheet = "Report"
+	if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+		return err
+	}
+
+	header := make([]any, len(data.Columns))
+	for i, column := range data.Columns {
+		header[i] = column
+	}
+	if err := file.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+
+	// Числа пишутся числами, чтобы по ним можно было считать в таблице
+	for i, row := range data.Rows {
+		cells := row.Cells()
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err := file.SetSheetRow(sheet, cell, &cells); err != nil {
+			return err
+		}
+	}
+
+	_, err := file.WriteTo(w)
+	return err
+}
+
+type jsonRenderer struct{}
+
+func (jsonRenderer) ContentType() string { return "application/json; charset=utf-8" }
+func (jsonRenderer) Extension() string   { return "json" }
+
+func (jsonRenderer) Render(w io.Writer, data *Dataset) error {
+	rows := data.Rows
+	if rows == nil {
+		rows = []Row{}
+	}
+
+	return json.NewEncoder(w).Encode(struct {
+		Title       string    `json:"title"`
+		GeneratedAt time.Time `json:"generatedAt"`
+		Params      []Param   `json:"params"`
+		Columns     []string  `json:"columns"`
+		Rows        []Row     `json:"rows"`
+		Charts      []Chart   `json:"charts,omitempty"`
+	}{
+		Title:       data.Title,
+		GeneratedAt: data.GeneratedAt,
+		Params:      data.Params,
+		Columns:     data.Columns,
+		Rows:        rows,
+		Charts:      data.Charts,
+	})
+}
@@ -0,0 +1,345 @@
+package report
+
+import (
+	"AirPort/package/apperrors"
+	"AirPort/package/delivery"
+	"AirPort/package/i18n"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	RunSucceeded = "succeeded"
+	RunFailed    = "failed"
+)
+
+var errScheduleNotFound = apperrors.NotFound("расписание не найдено")
+
+// cronParser понимает стандартные выражения из пяти полей и сокращения вида @weekly
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type Schedule struct {
+	Id         int       `db:"id" json:"id"`
+	Name       string    `db:"name" json:"name" binding:"required,max=255"`
+	Cron       string    `db:"cron" json:"cron" binding:"required"`
+	Params     Report    `db:"params" json:"params"`
+	Emails     []string  `db:"emails" json:"emails" binding:"omitempty,max=50,dive,email"`
+	WebhookUrl string    `db:"webhook_url" json:"webhookUrl" binding:"omitempty,url"`
+	Enabled    bool      `db:"enabled" json:"enabled"`
+	NextRun    time.Time `db:"next_run" json:"nextRun"`
+	CreatedAt  time.Time `db:"created_at" json:"createdAt"`
+	Language   string    `db:"language" json:"language" binding:"omitempty,oneof=ru en"`
+}
+
+type ScheduleRun struct {
+	Id         int        `db:"id" json:"id"`
+	ScheduleId int        `db:"schedule_id" json:"scheduleId"`
+	RequestId  string     `db:"request_id" json:"requestId"`
+	Status     string     `db:"status" json:"status"`
+	Error      string     `db:"error" json:"error,omitempty"`
+	ReportId   string     `db:"report_id" json:"reportId,omitempty"`
+	StartedAt  time.Time  `db:"started_at" json:"startedAt"`
+	FinishedAt *time.Time `db:"finished_at" json:"finishedAt,omitempty"`
+}
+
+func (s *Schedule) Validate() error {
+	if _, err := cronParser.Parse(s.Cron); err != nil {
+		return apperrors.Field("cron", "неверное cron выражение: %v", err)
+	}
+	if err := s.Params.Validate(); err != nil {
+		var appErr *apperrors.Error
+		if errors.As(err, &appErr) {
+			return appErr.WithFieldPrefix("params")
+		}
+		return err
+	}
+
+	if len(s.Emails) == 0 && s.WebhookUrl == "" {
+		return apperrors.Field("emails", "не указаны получатели отчёта")
+	}
+	// Тег url пропускает любые схемы, а webhook вызывается только по HTTP
+	if s.WebhookUrl != "" {
+		u, err := url.Parse(s.WebhookUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return apperrors.Field("webhookUrl", "ожидается адрес http или https")
+		}
+		if err := delivery.CheckHost(u.Hostname()); err != nil {
+			return apperrors.Field("webhookUrl", "адрес во внутренней сети запрещён")
+		}
+	}
+
+	return nil
+}
+
+func (s *Schedule) next(after time.Time) (time.Time, error) {
+	schedule, err := cronParser.Parse(s.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("неверное cron выражение: %w", err)
+	}
+
+	return schedule.Next(after), nil
+}
+
+func (s *Schedule) Create(ctx context.Context, db *pgxpool.Pool) error {
+	nextRun, err := s.next(time.Now())
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO Report_Schedules (name, cron, params, emails, webhook_url, enabled, next_run, language)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, next_run, created_at
+	`
+
+	if err := db.QueryRow(
+		ctx,
+		query,
+		s.Name,
+		s.Cron,
+		s.Params,
+		s.emails(),
+		s.WebhookUrl,
+		s.Enabled,
+		nextRun,
+		s.language(ctx),
+	).Scan(&s.Id, &s.NextRun, &s.CreatedAt); err != nil {
+		return fmt.Errorf("ошибка при создании расписания: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Schedule) Update(ctx context.Context, db *pgxpool.Pool) error {
+	nextRun, err := s.next(time.Now())
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE Report_Schedules
+		SET name = $2, cron = $3, params = $4, emails = $5, webhook_url = $6, enabled = $7, next_run = $8, language = $9
+		WHERE id = $1
+		RETURNING next_run, created_at
+	`
+
+	err = db.QueryRow(
+		ctx,
+		query,
+		s.Id,
+		s.Name,
+		s.Cron,
+		s.Params,
+		s.emails(),
+		s.WebhookUrl,
+		s.Enabled,
+		nextRun,
+		s.language(ctx),
+	).Scan(&s.NextRun, &s.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return errScheduleNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении расписания: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Schedule) Delete(ctx context.Context, db *pgxpool.Pool) error {
+	tag, err := db.Exec(ctx, `DELETE FROM Report_Schedules WHERE id = $1`, s.Id)
+	if err != nil {
+		return fmt.Errorf("ошибка при удалении расписания: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return errScheduleNotFound
+	}
+
+	return nil
+}
+
+func (s *Schedule) GetAll(ctx context.Context, db *pgxpool.Pool) ([]Schedule, error) {
+	query := `
+		SELECT id, name, cron, params, emails, webhook_url, enabled, next_run, created_at, language
+		FROM Report_Schedules
+		ORDER BY id
+	`
+
+	rows, err := db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении расписаний: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []Schedule
+	for rows.Next() {
+		var schedule Schedule
+		if err := rows.Scan(
+			&schedule.Id,
+			&schedule.Name,
+			&schedule.Cron,
+			&schedule.Params,
+			&schedule.Emails,
+			&schedule.WebhookUrl,
+			&schedule.Enabled,
+			&schedule.NextRun,
+			&schedule.CreatedAt,
+			&schedule.Language,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка при получении расписаний: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при получении расписаний: %w", err)
+	}
+
+	return schedules, nil
+}
+
+func (s *Schedule) GetRuns(ctx context.Context, db *pgxpool.Pool, limit int) ([]ScheduleRun, error) {
+	query := `
+		SELECT id, schedule_id, request_id, status, error, report_id, started_at, finished_at
+		FROM Report_Schedule_Runs
+		WHERE schedule_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	rows, err := db.Query(ctx, query, s.Id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории запусков: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []ScheduleRun
+	for rows.Next() {
+		var run ScheduleRun
+		if err := rows.Scan(
+			&run.Id,
+			&run.ScheduleId,
+			&run.RequestId,
+			&run.Status,
+			&run.Error,
+			&run.ReportId,
+			&run.StartedAt,
+			&run.FinishedAt,
+		); err != nil {
+			return nil, fmt.Errorf("ошибка при получении истории запусков: %w", err)
+		}
+		runs = append(runs, run)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при получении истории запусков: %w", err)
+	}
+
+	return runs, nil
+}
+
+// language возвращает язык писем; если он не задан, берётся язык запроса
+func (s *Schedule) language(ctx context.Context) string {
+	if s.Language == "" {
+		s.Language = string(i18n.FromContext(ctx))
+	}
+
+	return s.Language
+}
+
+func (s *Schedule) emails() []string {
+	if s.Emails == nil {
+		return []string{}
+	}
+
+	return s.Emails
+}
+
+// claimDue забирает одно наступившее расписание и сразу сдвигает его следующий запуск.
+// SKIP LOCKED не даёт двум экземплярам сервера выполнить одно расписание дважды.
+func claimDue(ctx context.Context, db *pgxpool.Pool, now time.Time) (*Schedule, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при начале транзакции: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		SELECT id, name, cron, params, emails, webhook_url, enabled, next_run, created_at, language
+		FROM Report_Schedules
+		WHERE enabled AND next_run <= $1
+		ORDER BY next_run
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var s Schedule
+	err = tx.QueryRow(ctx, query, now).Scan(
+		&s.Id,
+		&s.Name,
+		&s.Cron,
+		&s.Params,
+		&s.Emails,
+		&s.WebhookUrl,
+		&s.Enabled,
+		&s.NextRun,
+		&s.CreatedAt,
+		&s.Language,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении расписания: %w", err)
+	}
+
+	nextRun, err := s.next(now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE Report_Schedules SET next_run = $2 WHERE id = $1`, s.Id, nextRun); err != nil {
+		return nil, fmt.Errorf("ошибка при обновлении расписания: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("ошибка при фиксации расписания: %w", err)
+	}
+
+	return &s, nil
+}
+
+func (r *ScheduleRun) start(ctx context.Context, db *pgxpool.Pool) error {
+	query := `
+		INSERT INTO Report_Schedule_Runs (schedule_id, request_id, status)
+		VALUES ($1, $2, 'running')
+		RETURNING id, started_at
+	`
+
+	if err := db.QueryRow(ctx, query, r.ScheduleId, r.RequestId).Scan(&r.Id, &r.StartedAt); err != nil {
+		return fmt.Errorf("ошибка при записи запуска расписания: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ScheduleRun) finish(ctx context.Context, db *pgxpool.Pool) error {
+	query := `
+		UPDATE Report_Schedule_Runs
+		SET status = $2, error = $3, report_id = $4, finished_at = NOW()
+		WHERE id = $1
+	`
+
+	if _, err := db.Exec(ctx, query, r.Id, r.Status, r.Error, r.ReportId); err != nil {
+		return fmt.Errorf("ошибка при записи результата расписания: %w", err)
+	}
+
+	return nil
+}
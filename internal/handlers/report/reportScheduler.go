@@ -0,0 +1,178 @@
+package report
+
+import (
+	"AirPort/package/database"
+	"AirPort/package/delivery"
+	"AirPort/package/i18n"
+	"AirPort/package/logs"
+	"AirPort/package/metrics"
+	"AirPort/package/requestid"
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+var errSchedulerStopped = errors.New("планировщик отчётов не запущен")
+
+// Scheduler выполняет наступившие расписания и рассылает отчёты получателям
+type Scheduler struct {
+	db         *pgxpool.Pool
+	store      *Store
+	mailer     *delivery.Mailer
+	webhook    *delivery.Webhook
+	interval   time.Duration
+	jobTimeout time.Duration
+
+	running atomic.Bool
+	stop    context.CancelFunc
+	done    chan struct{}
+}
+
+func NewScheduler(db *pgxpool.Pool, store *Store, mailer *delivery.Mailer, webhook *delivery.Webhook, interval, jobTimeout time.Duration) *Scheduler {
+	return &Scheduler{
+		db:         db,
+		store:      store,
+		mailer:     mailer,
+		webhook:    webhook,
+		interval:   interval,
+		jobTimeout: jobTimeout,
+	}
+}
+
+func (s *Scheduler) Start() {
+	var ctx context.Context
+	ctx, s.stop = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go s.loop(ctx)
+}
+
+func (s *Scheduler) Stop() {
+	if s.stop == nil {
+		return
+	}
+	s.stop()
+	<-s.done
+}
+
+// Check используется проверкой готовности
+func (s *Scheduler) Check(ctx context.Context) error {
+	if !s.running.Load() {
+		return errSchedulerStopped
+	}
+
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.done)
+
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) runDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		schedule, err := claimDue(ctx, s.db, time.Now())
+		if err != nil {
+			logs.NewLog(ctx, "Планировщик отчётов", "report", err)
+			return
+		}
+		if schedule == nil {
+			return
+		}
+
+		s.run(ctx, schedule)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, schedule *Schedule) {
+	// У каждого запуска свой ID, он же уходит получателям в письме и webhook
+	run := &ScheduleRun{ScheduleId: schedule.Id, RequestId: requestid.New()}
+	ctx = requestid.NewContext(ctx, run.RequestId)
+	ctx = logs.WithAttrs(ctx, "report_schedule", schedule.Id)
+
+	if err := run.start(ctx, s.db); err != nil {
+		logs.NewLog(ctx, "Планировщик отчётов", "report", err)
+		return
+	}
+
+	run.Status = RunSucceeded
+	err := s.execute(ctx, schedule, run)
+	if err != nil {
+		run.Status = RunFailed
+		run.Error = err.Error()
+	}
+	logs.NewLog(ctx, "Запуск расписания отчёта", "report", err)
+
+	if err := run.finish(context.WithoutCancel(ctx), s.db); err != nil {
+		logs.NewLog(ctx, "Планировщик отчётов", "report", err)
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, schedule *Schedule, run *ScheduleRun) error {
+	ctx, cancel := context.WithTimeout(ctx, s.jobTimeout)
+	defer cancel()
+
+	file, err := database.RetryRead(ctx, s.db, schedule.Params.GetNewReportData)
+	if err != nil {
+		return err
+	}
+	metrics.ReportsGenerated.WithLabelValues(schedule.Params.reportType(), schedule.Params.format()).Inc()
+
+	if run.ReportId, err = s.store.Save(file); err != nil {
+		return err
+	}
+
+	attachment := delivery.Attachment{
+		Name:        fmt.Sprintf("report-%s.%s", time.Now().Format(DateFormat), file.Extension),
+		ContentType: file.ContentType,
+		Data:        file.Data,
+	}
+
+	lang := i18n.Lang(schedule.Language)
+
+	var errs []error
+	if len(schedule.Emails) > 0 {
+		if err := s.mailer.Send(ctx, delivery.Message{
+			To:          schedule.Emails,
+			Subject:     i18n.Translate(lang, "Отчёт: %s", schedule.Name),
+			Body:        i18n.Translate(lang, "Отчёт «%s» по расписанию %s.\nID запуска: %s", schedule.Name, schedule.Cron, run.RequestId),
+			Attachments: []delivery.Attachment{attachment},
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if schedule.WebhookUrl != "" {
+		metadata := map[string]any{
+			"scheduleId": schedule.Id,
+			"name":       schedule.Name,
+			"reportId":   run.ReportId,
+			"requestId":  run.RequestId,
+			"params":     schedule.Params,
+		}
+		if err := s.webhook.Post(ctx, schedule.WebhookUrl, metadata, attachment); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
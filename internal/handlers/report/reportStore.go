@@ -0,0 +1,82 @@
+package report
+
+import (
+	"AirPort/package/apperrors"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var errReportNotFound = apperrors.NotFound("отчёт не найден")
+
+// Store хранит сохранённые отчёты, каждый в своём файле с уникальным ID
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) Save(file *File) (string, error) {
+	if err := os.MkdirAll(s.dir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("ошибка при создании директории: %w", err)
+	}
+
+	id, err := newReportID()
+	if err != nil {
+		return "", err
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы никто не получил недописанный отчёт
+	tmp, err := os.CreateTemp(s.dir, id+"-*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("ошибка при создании файла отчёта: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(file.Data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("ошибка при записи отчёта: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("ошибка при записи отчёта: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, id+"."+file.Extension)); err != nil {
+		return "", fmt.Errorf("ошибка при сохранении отчёта: %w", err)
+	}
+
+	return id, nil
+}
+
+// Path возвращает путь к сохранённому отчёту по его ID
+func (s *Store) Path(id string) (string, error) {
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 32 {
+		return "", errReportNotFound
+	}
+
+	// Формат отчёта хранится в расширении файла
+	matches, err := filepath.Glob(filepath.Join(s.dir, id+".*"))
+	if err != nil {
+		return "", fmt.Errorf("ошибка при поиске отчёта: %w", err)
+	}
+	for _, path := range matches {
+		if filepath.Ext(path) != ".tmp" {
+			return path, nil
+		}
+	}
+
+	return "", errReportNotFound
+}
+
+func newReportID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("ошибка при создании ID отчёта: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,33 @@
+package tickets
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+type ticketsResponse struct {
+	Rows []UserTicketResponse `json:"rows"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Билеты"
+
+	router.DocV1(http.MethodGet, "/me/tickets", openapi.Operation{
+		Tag: tag, Summary: "Билеты текущего пользователя", Auth: true,
+		Response: ticketsResponse{},
+	})
+	router.DocV1(http.MethodPost, "/me/tickets", openapi.Operation{
+		Tag: tag, Summary: "Покупка билета", Auth: true,
+		Body: BookTicketRequest{}, Status: http.StatusCreated, Response: Ticket{},
+	})
+
+	router.Doc(http.MethodPost, "/ticket/getUserTickets", openapi.Operation{
+		Tag: tag, Summary: "Билеты пользователя по ID",
+		Body: UserTicketsRequest{}, Response: ticketsResponse{},
+	})
+	router.Doc(http.MethodPost, "/ticket/createUserTickets", openapi.Operation{
+		Tag: tag, Summary: "Покупка билета для пользователя по ID",
+		Body: CreateTicketRequest{}, Response: handlers.MessageResponse{},
+	})
+}
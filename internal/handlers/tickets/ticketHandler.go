@@ -2,11 +2,13 @@ package tickets
 
 import (
 	"AirPort/internal/handlers"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
 	"AirPort/package/logs"
+	"AirPort/package/metrics"
 	"net/http"
 
-	"fmt"
-
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
@@ -19,26 +21,26 @@ func NewHandler(db *pgxpool.Pool) handlers.Handlers {
 	return &Handler{db: db}
 }
 
-func (h *Handler) RegisterHandler(router *gin.Engine) {
-	router.POST("/ticket/getUserTickets", h.GetUserTickets)
-	router.POST("/ticket/createUserTickets", h.CreateUserTicket)
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.V1.GET("/me/tickets", auth.Required(), h.GetMyTickets)
+	router.V1.POST("/me/tickets", auth.Required(), h.BookTicket)
+
+	router.Deprecated(http.MethodPost, "/ticket/getUserTickets", "/me/tickets", h.GetUserTickets)
+	router.Deprecated(http.MethodPost, "/ticket/createUserTickets", "/me/tickets", h.CreateUserTicket)
 }
 
 func (h *Handler) GetUserTickets(c *gin.Context) {
-	var request struct {
-		UserId int `json:"user_id"`
-	}
-	if err := c.ShouldBindJSON(&request); err != nil {
-		logs.NewLog("Ticket", "ticket", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	var request UserTicketsRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
+	logs.SetUserID(c, request.UserId)
 	ticket := Ticket{UserId: request.UserId}
-	userTickets, err := ticket.GetAllUserTickets(h.db)
+	userTickets, err := database.RetryRead(c.Request.Context(), h.db, ticket.GetAllUserTickets)
 	if err != nil {
-		logs.NewLog("Ticket", "ticket", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		c.Error(err)
 		return
 	}
 
@@ -51,23 +53,54 @@ func (h *Handler) GetUserTickets(c *gin.Context) {
 }
 
 func (h *Handler) CreateUserTicket(c *gin.Context) {
-	var Ticket Ticket
-	if err := c.ShouldBindJSON(&Ticket); err != nil {
-		if logErr := logs.NewLog("Билет", "ticket", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	var request CreateTicketRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ticket := request.Ticket()
+	if err := ticket.CreateNewTicket(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := Ticket.CreateNewTicket(h.db); err != nil {
-		if logErr := logs.NewLog("Билет", "ticket", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	metrics.TicketsIssued.Inc()
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "Создано")})
+}
+
+func (h *Handler) GetMyTickets(c *gin.Context) {
+	ticket := Ticket{UserId: auth.FromContext(c).UserID}
+
+	userTickets, err := database.RetryRead(c.Request.Context(), h.db, ticket.GetAllUserTickets)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if userTickets == nil {
+		userTickets = []UserTicketResponse{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rows": userTickets})
+}
+
+func (h *Handler) BookTicket(c *gin.Context) {
+	var request BookTicketRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	ticket := Ticket{
+		UserId:    auth.FromContext(c).UserID,
+		FlightId:  request.FlightId,
+		FareClass: request.FareClass,
+	}
+	if err := ticket.CreateNewTicket(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Создано"})
+	metrics.TicketsIssued.Inc()
+	c.JSON(http.StatusCreated, ticket)
 }
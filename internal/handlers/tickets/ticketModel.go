@@ -1,6 +1,8 @@
 package tickets
 
 import (
+	"AirPort/package/apperrors"
+	"AirPort/package/database"
 	"context"
 	"fmt"
 	"math/rand"
@@ -14,9 +16,25 @@ type Ticket struct {
 	FlightId   int    `json:"flight_id" db:"flightId"`
 	SeatNumber string `json:"seat_number" db:"seatNumber"`
 	Price      int    `json:"price" db:"price"`
+	FareClass  string `json:"fare_class" db:"fareClass"`
 }
 
-func (t *Ticket) CreateNewTicket(db *pgxpool.Pool) error {
+const DefaultFareClass = "economy"
+
+var fareClasses = map[string]bool{
+	"economy":  true,
+	"business": true,
+	"first":    true,
+}
+
+func (t *Ticket) CreateNewTicket(ctx context.Context, db *pgxpool.Pool) error {
+	if t.FareClass == "" {
+		t.FareClass = DefaultFareClass
+	}
+	if !fareClasses[t.FareClass] {
+		return apperrors.Field("fare_class", "неизвестный класс обслуживания: %s", t.FareClass)
+	}
+
 	randomTikcetPrice := rand.Intn(35000-19000+1) + 19000
 
 	letters := []rune{'A', 'B', 'C'}
@@ -24,23 +42,24 @@ func (t *Ticket) CreateNewTicket(db *pgxpool.Pool) error {
 	number := rand.Intn(21)
 	seatNumber := fmt.Sprintf("%c%02d", letter, number)
 
-	ctx := context.Background()
-
 	query := `
-		INSERT INTO Tickets(userId, flightId, seatNumber, price)
+		INSERT INTO Tickets(userId, flightId, seatNumber, price, fareClass)
 		VALUES
-			($1, $2, $3, $4)
+			($1, $2, $3, $4, $5)
 		RETURNING id
 	`
 
-	var ticket_id int
-
-	err := db.QueryRow(ctx, query, t.UserId, t.FlightId, seatNumber, randomTikcetPrice).Scan(
-		&ticket_id,
+	err := db.QueryRow(ctx, query, t.UserId, t.FlightId, seatNumber, randomTikcetPrice, t.FareClass).Scan(
+		&t.Id,
 	)
 	if err != nil {
+		if database.IsForeignKeyViolation(err) {
+			return apperrors.NotFound("пользователь или рейс не найден").Wrap(err)
+		}
 		return err
 	}
+	t.SeatNumber = seatNumber
+	t.Price = randomTikcetPrice
 
 	addNotificationQuery := `
 		INSERT INTO Notifications (user_id, ticket_id)
@@ -48,7 +67,7 @@ func (t *Ticket) CreateNewTicket(db *pgxpool.Pool) error {
 			($1, $2)
 	`
 
-	_, err = db.Exec(ctx, addNotificationQuery, t.UserId, ticket_id)
+	_, err = db.Exec(ctx, addNotificationQuery, t.UserId, t.Id)
 	if err != nil {
 		return err
 	}
@@ -60,13 +79,12 @@ type UserTicketResponse struct {
 	FlightNumber string `json:"flightId"`
 	SeatNumber   string `json:"seatNumber"`
 	Price        int    `json:"price"`
+	FareClass    string `json:"fareClass"`
 }
 
-func (t *Ticket) GetAllUserTickets(db *pgxpool.Pool) ([]UserTicketResponse, error) {
-	ctx := context.Background()
-
+func (t *Ticket) GetAllUserTickets(ctx context.Context, db *pgxpool.Pool) ([]UserTicketResponse, error) {
 	query := `
-      SELECT Board.flightNumber, Tickets.seatNumber, Tickets.price
+      SELECT Board.flightNumber, Tickets.seatNumber, Tickets.price, Tickets.fareClass
       FROM Tickets
       JOIN Board ON Board.id = Tickets.flightId
       WHERE Tickets.userId = $1
@@ -81,7 +99,7 @@ func (t *Ticket) GetAllUserTickets(db *pgxpool.Pool) ([]UserTicketResponse, erro
 	var allTickets []UserTicketResponse
 	for rows.Next() {
 		var ticket UserTicketResponse
-		if err := rows.Scan(&ticket.FlightNumber, &ticket.SeatNumber, &ticket.Price); err != nil {
+		if err := rows.Scan(&ticket.FlightNumber, &ticket.SeatNumber, &ticket.Price, &ticket.FareClass); err != nil {
 			return nil, err
 		}
 		allTickets = append(allTickets, ticket)
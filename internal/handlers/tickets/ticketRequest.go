@@ -0,0 +1,25 @@
+package tickets
+
+type UserTicketsRequest struct {
+	UserId int `json:"user_id" binding:"required,gt=0"`
+}
+
+type CreateTicketRequest struct {
+	UserId    int    `json:"user_id" binding:"required,gt=0"`
+	FlightId  int    `json:"flight_id" binding:"required,gt=0"`
+	FareClass string `json:"fare_class" binding:"omitempty,oneof=economy business first"`
+}
+
+func (r CreateTicketRequest) Ticket() Ticket {
+	return Ticket{
+		UserId:    r.UserId,
+		FlightId:  r.FlightId,
+		FareClass: r.FareClass,
+	}
+}
+
+// BookTicketRequest — покупка билета в /api/v1, пользователь берётся из токена
+type BookTicketRequest struct {
+	FlightId  int    `json:"flight_id" binding:"required,gt=0"`
+	FareClass string `json:"fare_class" binding:"omitempty,oneof=economy business first"`
+}
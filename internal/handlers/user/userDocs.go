@@ -0,0 +1,57 @@
+package user
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+type notificationsResponse struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Пользователи"
+
+	router.DocV1(http.MethodPost, "/users", openapi.Operation{
+		Tag: tag, Summary: "Регистрация пользователя",
+		Body: RegisterRequest{}, Response: handlers.TokenResponse{},
+	})
+	router.DocV1(http.MethodPost, "/sessions", openapi.Operation{
+		Tag: tag, Summary: "Вход по логину и паролю",
+		Body: LoginRequest{}, Response: handlers.TokenResponse{},
+	})
+	router.DocV1(http.MethodGet, "/me", openapi.Operation{
+		Tag: tag, Summary: "Профиль текущего пользователя", Auth: true,
+		Response: Users{},
+	})
+	router.DocV1(http.MethodDelete, "/me", openapi.Operation{
+		Tag: tag, Summary: "Удаление учётной записи", Auth: true,
+		Status: http.StatusNoContent,
+	})
+	router.DocV1(http.MethodGet, "/me/notifications", openapi.Operation{
+		Tag: tag, Summary: "Уведомления текущего пользователя", Auth: true,
+		Response: notificationsResponse{},
+	})
+	router.DocV1(http.MethodPut, "/me/language", openapi.Operation{
+		Tag: tag, Summary: "Смена языка", Description: "Возвращает новый токен с языком в claim lang.", Auth: true,
+		Body: MyLanguageRequest{}, Response: handlers.TokenResponse{},
+	})
+
+	router.Doc(http.MethodPost, "/users/registration", openapi.Operation{
+		Tag: tag, Summary: "Регистрация пользователя",
+		Body: RegisterRequest{}, Response: handlers.TokenResponse{},
+	})
+	router.Doc(http.MethodPost, "/users/login", openapi.Operation{
+		Tag: tag, Summary: "Вход по логину и паролю",
+		Body: LoginRequest{}, Response: handlers.TokenResponse{},
+	})
+	router.Doc(http.MethodDelete, "/user/deleteUser", openapi.Operation{
+		Tag: tag, Summary: "Удаление пользователя по ID",
+		Body: UserIDRequest{}, Response: handlers.MessageResponse{},
+	})
+	router.Doc(http.MethodPost, "/user/get_user_notifications", openapi.Operation{
+		Tag: tag, Summary: "Уведомления пользователя по ID",
+		Body: UserIDRequest{}, Response: notificationsResponse{},
+	})
+}
@@ -1,64 +1,57 @@
 package user
 
 import (
+	"AirPort/internal/config"
 	"AirPort/internal/handlers"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
 	"AirPort/package/logs"
-	"fmt"
+	"AirPort/package/metrics"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
-const (
-	emailAlreadyExistError    = "email already exist"
-	usernameAlreadyExistError = "username already exist"
-)
-
 type Handler struct {
-	db *pgxpool.Pool
+	db   *pgxpool.Pool
+	auth config.AuthConfig
 }
 
-func NewHandler(db *pgxpool.Pool) handlers.Handlers {
-	return &Handler{db: db}
+func NewHandler(db *pgxpool.Pool, auth config.AuthConfig) handlers.Handlers {
+	return &Handler{db: db, auth: auth}
 }
 
-func (h *Handler) RegisterHandler(router *gin.Engine) {
-	router.POST("/users/registration", h.Register)
-	router.POST("/users/login", h.Login)
-	router.DELETE("/user/deleteUser", h.DeleteUser)
-	router.POST("/user/get_user_notifications", h.GetUserNotifications)
-}
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.V1.POST("/users", h.Register)
+	router.V1.POST("/sessions", h.Login)
 
-func (h *Handler) Register(c *gin.Context) {
-	var newUser Users
+	me := router.V1.Group("/me", auth.Required())
+	me.GET("", h.GetMe)
+	me.DELETE("", h.DeleteMe)
+	me.GET("/notifications", h.GetMyNotifications)
+	me.PUT("/language", h.UpdateMyLanguage)
 
-	if err := c.ShouldBindJSON(&newUser); err != nil {
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
-		return
-	}
+	router.Deprecated(http.MethodPost, "/users/registration", "/users", h.Register)
+	router.Deprecated(http.MethodPost, "/users/login", "/sessions", h.Login)
+	router.Deprecated(http.MethodDelete, "/user/deleteUser", "/me", h.DeleteUser)
+	router.Deprecated(http.MethodPost, "/user/get_user_notifications", "/me/notifications", h.GetUserNotifications)
+}
 
-	if len(newUser.Password) < 6 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "пароль должен быть не менее 6 символов"})
+func (h *Handler) Register(c *gin.Context) {
+	var request RegisterRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	token, err := newUser.RegisterUser(h.db)
+	newUser := request.User()
+	token, err := newUser.RegisterUser(c.Request.Context(), h.db, auth.Options(h.auth))
 	if err != nil {
-		if err.Error() == usernameAlreadyExistError {
-			c.JSON(http.StatusConflict, gin.H{"error": "пользователь с таким username уже сущевствует"})
-			return
-		} else if err.Error() == emailAlreadyExistError {
-			c.JSON(http.StatusConflict, gin.H{"error": "пользователь с таким email уже сущевствует"})
-			return
-		}
-		fmt.Printf("Ошибка при попытке регистрации: %s", err)
-		if logErr := logs.NewLog("Регистрация", "user", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr.Error())
-		}
-
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+		c.Error(err)
 		return
 	}
 
@@ -66,59 +59,58 @@ func (h *Handler) Register(c *gin.Context) {
 }
 
 func (h *Handler) Login(c *gin.Context) {
-	var loginUser Users
-	if err := c.ShouldBindJSON(&loginUser); err != nil {
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	var request LoginRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	token, err := loginUser.LoginUser(h.db)
+	loginUser := Users{Username: request.Username, Password: request.Password}
+
+	token, err := loginUser.LoginUser(c.Request.Context(), h.db, auth.Options(h.auth))
 	if err != nil {
-		if logErr := logs.NewLog("Вход в аккаунт", "user", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr.Error())
+		if errors.Is(err, apperrors.ErrUnauthorized) {
+			metrics.FailedLogins.Inc()
 		}
-		fmt.Printf("Ошибка при попытке входа в аккаунт: %s", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "ошибка при авторизации"})
+		c.Error(err)
 		return
 	}
 
+	logs.SetUserID(c, loginUser.Id)
 	c.JSON(http.StatusOK, gin.H{"token": token})
 }
 
 func (h *Handler) DeleteUser(c *gin.Context) {
-	var userToDelete Users
-	if err := c.ShouldBindJSON(&userToDelete); err != nil {
-		fmt.Println("Ошибка при чтеннии JSON: %w", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "внутренняя ошибка сервера"})
+	var request UserIDRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := userToDelete.DeleteUser(h.db); err != nil {
-		if logErr := logs.NewLog("Удаление пользователя", "user", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr.Error())
-		}
-		fmt.Printf("Ошибка при попытке удаления аккаунта: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	userToDelete := Users{Id: request.Id}
+
+	logs.SetUserID(c, userToDelete.Id)
+	if err := userToDelete.DeleteUser(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "удалено"})
+
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "удалено")})
 }
 
 func (h *Handler) GetUserNotifications(c *gin.Context) {
-	var userToGet Users
-	if err := c.ShouldBindJSON(&userToGet); err != nil {
-		fmt.Println("Ошибка при чтеннии JSON: %w", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "внутренняя ошибка сервера"})
+	var request UserIDRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
 		return
 	}
 
-	notifications, err := userToGet.GetAllNotifications(h.db)
+	userToGet := Users{Id: request.Id}
+
+	logs.SetUserID(c, userToGet.Id)
+	notifications, err := database.RetryRead(c.Request.Context(), h.db, userToGet.GetAllNotifications)
 	if err != nil {
-		if logErr := logs.NewLog("Получение уведомлений", "user", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s\n", logErr.Error())
-		}
-		fmt.Printf("Ошибка при получении уведомлений: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "ошибка при получении уведомлений"})
+		c.Error(err)
 		return
 	}
 
@@ -126,3 +118,57 @@ func (h *Handler) GetUserNotifications(c *gin.Context) {
 		"notifications": notifications,
 	})
 }
+
+func (h *Handler) GetMe(c *gin.Context) {
+	me := Users{Id: auth.FromContext(c).UserID}
+
+	profile, err := database.RetryRead(c.Request.Context(), h.db, me.GetByID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (h *Handler) DeleteMe(c *gin.Context) {
+	me := Users{Id: auth.FromContext(c).UserID}
+
+	if err := me.DeleteUser(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) GetMyNotifications(c *gin.Context) {
+	me := Users{Id: auth.FromContext(c).UserID}
+
+	notifications, err := database.RetryRead(c.Request.Context(), h.db, me.GetAllNotifications)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+	})
+}
+
+func (h *Handler) UpdateMyLanguage(c *gin.Context) {
+	var request MyLanguageRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	me := Users{Username: auth.FromContext(c).Username, Language: request.Language}
+	token, err := me.UpdateLanguage(c.Request.Context(), h.db, auth.Options(h.auth))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
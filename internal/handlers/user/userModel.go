@@ -1,11 +1,17 @@
 package user
 
 import (
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
+	"AirPort/package/tracing"
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -14,43 +20,44 @@ var (
 	TimeFormat = "2006-01-02 15:04:05"
 )
 
-type SecretJwt struct {
-	Secret string `env:SECRET_JWT`
-}
-
-func (s *SecretJwt) ReadSecret() error {
-	err := cleanenv.ReadConfig("internal/config/.env", s)
-	if err != nil {
-		return fmt.Errorf("ошибка при получении секрета: %w", err)
-	}
+var (
+	errInvalidCredentials = apperrors.Unauthorized("неверный логин или пароль")
+	errUsernameTaken      = apperrors.Conflict("пользователь с таким username уже существует")
+	errEmailTaken         = apperrors.Conflict("пользователь с таким email уже существует")
+	errUserNotFound       = apperrors.NotFound("пользователь не найден")
+)
 
-	return nil
+func (u *Users) GenerateJWT(opts auth.Options) (string, error) {
+	return auth.Sign(opts, auth.Claims{
+		UserID:      u.Id,
+		Username:    u.Username,
+		Email:       u.Email,
+		Employee:    u.UserRole,
+		Name:        u.Name,
+		MasterAdmin: u.MasterAdmin,
+		Lang:        u.Language,
+	})
 }
 
-func (u *Users) GenerateJWT() (string, error) {
-	var Secret SecretJwt
-	if err := Secret.ReadSecret(); err != nil {
-		fmt.Println("ошибка при попытке прочитать секрет: %w", err)
-		return "", err
-	}
-
-	claims := jwt.MapClaims{
-		"id":          u.Id,
-		"username":    u.Username,
-		"email":       u.Email,
-		"role":        u.UserRole,
-		"name":        u.Name,
-		"masterAdmin": u.MasterAdmin,
-	}
+// Roles читает права пользователя из базы для auth.RequireEmployee
+func Roles(db *pgxpool.Pool) auth.RoleLookup {
+	query := `
+		SELECT userRole, masterAdmin
+		FROM Users
+		WHERE id = $1
+	`
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return func(ctx context.Context, userID int) (*auth.Role, error) {
+		var role auth.Role
+		if err := db.QueryRow(ctx, query, userID).Scan(&role.Employee, &role.MasterAdmin); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("ошибка при получении роли пользователя: %w", err)
+		}
 
-	signedToken, err := token.SignedString([]byte(Secret.Secret))
-	if err != nil {
-		return "", fmt.Errorf("ошибка при создании токена: %w", err)
+		return &role, nil
 	}
-
-	return signedToken, nil
 }
 
 func (u *Users) HashPassword(password string) (string, error) {
@@ -62,19 +69,26 @@ func (u *Users) HashPassword(password string) (string, error) {
 	return string(hashedPassword), nil
 }
 
+// comparePassword проверяет пароль в отдельном спане: bcrypt намеренно медленный
+func comparePassword(ctx context.Context, hash, password string) error {
+	_, span := tracing.Start(ctx, "bcrypt.compare")
+	defer span.End()
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+}
+
 type Users struct {
 	Id          int    `db:"id" json:"id"`
 	Username    string `db:"username" json:"username"`
 	Name        string `db:"name" json:"name"`
-	Password    string `db:"password" json:"password"`
+	Password    string `db:"password" json:"password,omitempty"`
 	Email       string `db:"email" json:"email"`
 	UserRole    bool   `db:"userRole" json:"userRole"`
-	MasterAdmin bool   `db:"masterAdmin"`
+	MasterAdmin bool   `db:"masterAdmin" json:"masterAdmin"`
+	Language    string `db:"language" json:"language"`
 }
 
-func (u *Users) CheckAccPassword(db *pgxpool.Pool) error {
-	ctx := context.Background()
-
+func (u *Users) CheckAccPassword(ctx context.Context, db *pgxpool.Pool) error {
 	getPasswordQuery := `
 		SELECT password
 		FROM Users
@@ -83,22 +97,20 @@ func (u *Users) CheckAccPassword(db *pgxpool.Pool) error {
 
 	var dbUser Users
 	if err := db.QueryRow(ctx, getPasswordQuery, u.Username).Scan(&dbUser.Password); err != nil {
-		return fmt.Errorf("ошибка при попытке получить пароль из базы данных: %s", err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errInvalidCredentials
+		}
+		return fmt.Errorf("ошибка при попытке получить пароль из базы данных: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(
-		[]byte(dbUser.Password),
-		[]byte(u.Password),
-	); err != nil {
-		return fmt.Errorf("неверные данные")
+	if err := comparePassword(ctx, dbUser.Password, u.Password); err != nil {
+		return errInvalidCredentials
 	}
 
 	return nil
 }
 
-func (u *Users) RegisterUser(db *pgxpool.Pool) (string, error) {
-	ctx := context.Background()
-
+func (u *Users) RegisterUser(ctx context.Context, db *pgxpool.Pool, opts auth.Options) (string, error) {
 	checkUsernameExist := `
 		SELECT COUNT(*) 
 		FROM Users 
@@ -110,7 +122,7 @@ func (u *Users) RegisterUser(db *pgxpool.Pool) (string, error) {
 		return "", fmt.Errorf("ошибка при проверке пользователя: %w", err)
 	}
 	if userCountWithUsername != 0 {
-		return "", fmt.Errorf("ошибка: %s", usernameAlreadyExistError)
+		return "", errUsernameTaken
 	}
 
 	checkEmailExist := `
@@ -124,17 +136,21 @@ func (u *Users) RegisterUser(db *pgxpool.Pool) (string, error) {
 		return "", fmt.Errorf("ошибка при проверке пользователя: %w", err)
 	}
 	if userCountWithEmail != 0 {
-		return "", fmt.Errorf("ошибка: %s", emailAlreadyExistError)
+		return "", errEmailTaken
+	}
+
+	// Без явного выбора сохраняется язык, на котором пришёл запрос регистрации
+	if u.Language == "" {
+		u.Language = string(i18n.FromContext(ctx))
 	}
 
 	InsertQuery := `
-		INSERT INTO Users (username, name, password, email, userRole)
+		INSERT INTO Users (username, name, password, email, userRole, language)
 		VALUES
-			($1, $2, $3, $4, false)
+			($1, $2, $3, $4, false, $5)
 		RETURNING id
 	`
 
-	var newUserId int
 	hashedPassword, err := u.HashPassword(u.Password)
 	if err != nil {
 		return "", err
@@ -147,11 +163,16 @@ func (u *Users) RegisterUser(db *pgxpool.Pool) (string, error) {
 		u.Name,
 		hashedPassword,
 		u.Email,
-	).Scan(&newUserId); err != nil {
+		u.Language,
+	).Scan(&u.Id); err != nil {
+		// Проверки выше не защищают от одновременной регистрации с теми же данными
+		if database.IsUniqueViolation(err) {
+			return "", apperrors.Conflict("пользователь с таким username или email уже существует").Wrap(err)
+		}
 		return "", fmt.Errorf("ошибка записи в бд при попытке регистрации: %w", err)
 	}
 
-	token, err := u.GenerateJWT()
+	token, err := u.GenerateJWT(opts)
 	if err != nil {
 		return "", err
 	}
@@ -159,11 +180,9 @@ func (u *Users) RegisterUser(db *pgxpool.Pool) (string, error) {
 	return token, nil
 }
 
-func (u *Users) LoginUser(db *pgxpool.Pool) (string, error) {
-	ctx := context.Background()
-
+func (u *Users) LoginUser(ctx context.Context, db *pgxpool.Pool, opts auth.Options) (string, error) {
 	query := `
-   	SELECT id, username, name, password, email, userrole, masterAdmin 
+   	SELECT id, username, name, password, email, userrole, masterAdmin, language 
    	FROM Users 
    	WHERE username = $1
 	`
@@ -177,24 +196,25 @@ func (u *Users) LoginUser(db *pgxpool.Pool) (string, error) {
 		&dbUser.Email,
 		&dbUser.UserRole,
 		&dbUser.MasterAdmin,
+		&dbUser.Language,
 	)
 
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errInvalidCredentials
+		}
 		return "", fmt.Errorf("ошибка авторизации: %w", err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(
-		[]byte(dbUser.Password),
-		[]byte(u.Password),
-	); err != nil {
-		return "", fmt.Errorf("неверные данные")
+	if err := comparePassword(ctx, dbUser.Password, u.Password); err != nil {
+		return "", errInvalidCredentials
 	}
 
 	u.Id = dbUser.Id
 	u.Email = dbUser.Email
 	u.UserRole = dbUser.UserRole
 
-	token, err := dbUser.GenerateJWT()
+	token, err := dbUser.GenerateJWT(opts)
 	if err != nil {
 		return "", err
 	}
@@ -202,38 +222,67 @@ func (u *Users) LoginUser(db *pgxpool.Pool) (string, error) {
 	return token, nil
 }
 
-func (u *Users) DeleteUser(db *pgxpool.Pool) error {
-	ctx := context.Background()
+// GetByID загружает профиль без хэша пароля
+func (u *Users) GetByID(ctx context.Context, db *pgxpool.Pool) (*Users, error) {
+	query := `
+		SELECT id, username, name, email, userrole, masterAdmin, language
+		FROM Users
+		WHERE id = $1
+	`
 
+	var profile Users
+	if err := db.QueryRow(ctx, query, u.Id).Scan(
+		&profile.Id,
+		&profile.Username,
+		&profile.Name,
+		&profile.Email,
+		&profile.UserRole,
+		&profile.MasterAdmin,
+		&profile.Language,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errUserNotFound
+		}
+		return nil, fmt.Errorf("ошибка при получении пользователя: %w", err)
+	}
+
+	return &profile, nil
+}
+
+func (u *Users) DeleteUser(ctx context.Context, db *pgxpool.Pool) error {
 	query := `
 		DELETE FROM Users
 		WHERE id = $1
 	`
 
-	_, err := db.Exec(ctx, query, u.Id)
+	result, err := db.Exec(ctx, query, u.Id)
 	if err != nil {
 		return fmt.Errorf("ошибка при удалении: %w", err)
 	}
+	if result.RowsAffected() == 0 {
+		return errUserNotFound
+	}
 
 	return nil
 }
 
-func (u *Users) UpdateUserRole(db *pgxpool.Pool) (string, error) {
-	ctx := context.Background()
-
+func (u *Users) UpdateUserRole(ctx context.Context, db *pgxpool.Pool, opts auth.Options) (string, error) {
 	updateQuery := `
 		UPDATE Users	
 		SET userrole = true 
 		WHERE username = $1
 	`
 
-	_, err := db.Exec(ctx, updateQuery, u.Username)
+	result, err := db.Exec(ctx, updateQuery, u.Username)
 	if err != nil {
 		return "", fmt.Errorf("ошибка при обновлении роли: %w", err)
 	}
+	if result.RowsAffected() == 0 {
+		return "", errUserNotFound
+	}
 
 	selectQuery := `
-		SELECT id, username, name, password, email, userrole 
+		SELECT id, username, name, password, email, userrole, masterAdmin, language 
 		FROM Users 
 		WHERE username = $1
 	`
@@ -246,11 +295,13 @@ func (u *Users) UpdateUserRole(db *pgxpool.Pool) (string, error) {
 		&userNewRole.Password,
 		&userNewRole.Email,
 		&userNewRole.UserRole,
+		&userNewRole.MasterAdmin,
+		&userNewRole.Language,
 	); err != nil {
 		return "", fmt.Errorf("ошибка при получении обновлённого пользователя: %w", err)
 	}
 
-	token, err := userNewRole.GenerateJWT()
+	token, err := userNewRole.GenerateJWT(opts)
 	if err != nil {
 		return "", fmt.Errorf("ошибка при генерации токена: %w", err)
 	}
@@ -258,18 +309,66 @@ func (u *Users) UpdateUserRole(db *pgxpool.Pool) (string, error) {
 	return token, nil
 }
 
+// Promote выдаёт роль сотрудника, а при masterAdmin — и права главного администратора.
+// Через API главного администратора назначить нельзя, поэтому первый создаётся из airportctl
+func (u *Users) Promote(ctx context.Context, db *pgxpool.Pool, masterAdmin bool) error {
+	query := `
+		UPDATE Users
+		SET userRole = true, masterAdmin = masterAdmin OR $2
+		WHERE username = $1
+	`
+
+	result, err := db.Exec(ctx, query, u.Username, masterAdmin)
+	if err != nil {
+		return fmt.Errorf("ошибка при обновлении роли: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateLanguage сохраняет язык сообщений и возвращает токен с новым значением
+func (u *Users) UpdateLanguage(ctx context.Context, db *pgxpool.Pool, opts auth.Options) (string, error) {
+	query := `
+		UPDATE Users
+		SET language = $1
+		WHERE username = $2
+		RETURNING id, username, name, email, userrole, masterAdmin, language
+	`
+
+	var updated Users
+	if err := db.QueryRow(ctx, query, u.Language, u.Username).Scan(
+		&updated.Id,
+		&updated.Username,
+		&updated.Name,
+		&updated.Email,
+		&updated.UserRole,
+		&updated.MasterAdmin,
+		&updated.Language,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errUserNotFound
+		}
+		return "", fmt.Errorf("ошибка при обновлении языка: %w", err)
+	}
+
+	return updated.GenerateJWT(opts)
+}
+
 type Notification struct {
 	SeatNumber string
 	Date       string
+	Message    string
 }
 
-func (u *Users) GetAllNotifications(db *pgxpool.Pool) ([]Notification, error) {
-	ctx := context.Background()
-
+func (u *Users) GetAllNotifications(ctx context.Context, db *pgxpool.Pool) ([]Notification, error) {
 	query := `
-	    SELECT Tickets.seatNumber, TO_CHAR(NOW(), 'YYYY-MM-DD HH24:MI:SS') as date
+	    SELECT Tickets.seatNumber, Board.flightNumber, TO_CHAR(NOW(), 'YYYY-MM-DD HH24:MI:SS') as date
 	    FROM Notifications
 		JOIN Tickets ON Tickets.id = ticket_id
+		JOIN Board ON Board.id = Tickets.flightId
 	    WHERE user_id = $1
 	`
 
@@ -281,10 +380,14 @@ func (u *Users) GetAllNotifications(db *pgxpool.Pool) ([]Notification, error) {
 
 	var notifications []Notification
 	for rows.Next() {
-		var noti Notification
-		if err := rows.Scan(&noti.SeatNumber, &noti.Date); err != nil {
+		var (
+			noti         Notification
+			flightNumber string
+		)
+		if err := rows.Scan(&noti.SeatNumber, &flightNumber, &noti.Date); err != nil {
 			return nil, fmt.Errorf("ошибка получения уведомлений: %w", err)
 		}
+		noti.Message = i18n.T(ctx, "Оформлен билет на рейс %s, место %s", flightNumber, noti.SeatNumber)
 		notifications = append(notifications, noti)
 	}
 
@@ -294,3 +397,23 @@ func (u *Users) GetAllNotifications(db *pgxpool.Pool) ([]Notification, error) {
 
 	return notifications, nil
 }
+
+// PurgeNotifications удаляет уведомления о билетах, купленных раньше before.
+// У уведомлений нет своей даты, поэтому возраст считается по билету, а если
+// дата покупки неизвестна — по вылету рейса, который всегда позже покупки
+func PurgeNotifications(ctx context.Context, db *pgxpool.Pool, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM Notifications
+		USING Tickets
+		JOIN Board ON Board.id = Tickets.flightId
+		WHERE Tickets.id = Notifications.ticket_id
+			AND COALESCE(Tickets.created_at, Board.departure) < $1
+	`
+
+	result, err := db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка при удалении уведомлений: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type RegisterRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+	Name     string `json:"name" binding:"max=255"`
+	Password string `json:"password" binding:"required,password"`
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Language string `json:"language" binding:"omitempty,oneof=ru en"`
+}
+
+func (r RegisterRequest) User() Users {
+	return Users{
+		Username: r.Username,
+		Name:     r.Name,
+		Password: r.Password,
+		Email:    r.Email,
+		Language: r.Language,
+	}
+}
+
+// LoginRequest не проверяет политику паролей: она действует только для новых паролей
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type MyLanguageRequest struct {
+	Language string `json:"language" binding:"required,oneof=ru en"`
+}
+
+type UserIDRequest struct {
+	Id int `json:"id" binding:"required,gt=0"`
+}
+
+// Credentials — логин и пароль сотрудника в запросах на изменение табло и токенов
+type Credentials struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+func (c Credentials) Check(ctx context.Context, db *pgxpool.Pool) error {
+	u := Users{Username: c.Username, Password: c.Password}
+	return u.CheckAccPassword(ctx, db)
+}
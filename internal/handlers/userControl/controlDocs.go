@@ -0,0 +1,38 @@
+package control
+
+import (
+	"AirPort/internal/handlers"
+	"AirPort/package/openapi"
+	"net/http"
+)
+
+func (h *Handler) Document(router *handlers.Router) {
+	const tag = "Мастер-токены"
+
+	router.DocV1(http.MethodGet, "/master-tokens", openapi.Operation{
+		Tag: tag, Summary: "Список мастер-токенов", Description: "Только для главного администратора.", Auth: true,
+		Response: []string{},
+	})
+	router.DocV1(http.MethodPost, "/master-tokens", openapi.Operation{
+		Tag: tag, Summary: "Выпуск мастер-токена", Description: "Только для главного администратора.", Auth: true,
+		Status: http.StatusCreated, Response: Token{},
+	})
+	router.DocV1(http.MethodPost, "/me/role", openapi.Operation{
+		Tag: tag, Summary: "Получение роли сотрудника по мастер-токену",
+		Description: "Возвращает новый токен с ролью сотрудника.", Auth: true,
+		Body: PromoteRequest{}, Response: handlers.TokenResponse{},
+	})
+
+	router.Doc(http.MethodGet, "/control/getTokens", openapi.Operation{
+		Tag: tag, Summary: "Список мастер-токенов",
+		Response: []string{},
+	})
+	router.Doc(http.MethodPost, "/control/generateToken", openapi.Operation{
+		Tag: tag, Summary: "Выпуск мастер-токена по логину и паролю",
+		Body: GenerateTokenRequest{}, Response: handlers.MessageResponse{},
+	})
+	router.Doc(http.MethodPost, "/control/checkValidToken", openapi.Operation{
+		Tag: tag, Summary: "Получение роли сотрудника по мастер-токену",
+		Body: CheckTokenRequest{}, Response: handlers.TokenResponse{},
+	})
+}
@@ -1,116 +1,136 @@
 package control
 
 import (
+	"AirPort/internal/config"
 	"AirPort/internal/handlers"
 	"AirPort/internal/handlers/user"
-	"AirPort/package/logs"
-	"fmt"
+	"AirPort/package/apperrors"
+	"AirPort/package/auth"
+	"AirPort/package/database"
+	"AirPort/package/i18n"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+var errInvalidToken = apperrors.Forbidden("недействительный мастер-токен")
+
 type Handler struct {
-	db *pgxpool.Pool
+	db   *pgxpool.Pool
+	auth config.AuthConfig
 }
 
-func NewHandler(db *pgxpool.Pool) handlers.Handlers {
-	return &Handler{db: db}
+func NewHandler(db *pgxpool.Pool, auth config.AuthConfig) handlers.Handlers {
+	return &Handler{db: db, auth: auth}
 }
 
-func (h *Handler) RegisterHandler(router *gin.Engine) {
-	router.GET("/control/getTokens", h.GetTokens)
-	router.POST("/control/generateToken", h.GenerateToken)
-	router.POST("/control/checkValidToken", h.CheckValidToken)
+func (h *Handler) RegisterHandler(router *handlers.Router) {
+	router.V1.GET("/master-tokens", auth.RequireMasterAdmin(), h.GetTokens)
+	router.V1.POST("/master-tokens", auth.RequireMasterAdmin(), h.IssueToken)
+	router.V1.POST("/me/role", auth.Required(), h.PromoteMe)
+
+	router.Deprecated(http.MethodGet, "/control/getTokens", "/master-tokens", h.GetTokens)
+	router.Deprecated(http.MethodPost, "/control/generateToken", "/master-tokens", h.GenerateToken)
+	router.Deprecated(http.MethodPost, "/control/checkValidToken", "/me/role", h.CheckValidToken)
 }
 
 func (h *Handler) GetTokens(c *gin.Context) {
 	var tokensToGet Token
 
-	tokens, err := tokensToGet.GetAllTokens(h.db)
+	tokens, err := database.RetryRead(c.Request.Context(), h.db, tokensToGet.GetAllTokens)
 	if err != nil {
-		if logErr := logs.NewLog("Токен", "control", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"message": "внутренняя ошибка сервера"})
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, tokens)
 }
 
 func (h *Handler) GenerateToken(c *gin.Context) {
-	type RequestData struct {
-		User  user.Users `json:"user"`
-		Token Token      `json:"token"`
-	}
-
-	var requestData RequestData
+	var requestData GenerateTokenRequest
 
-	if err := c.ShouldBindJSON(&requestData); err != nil {
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	if err := handlers.BindJSON(c, &requestData); err != nil {
+		c.Error(err)
 		return
 	}
 
-	err := requestData.User.CheckAccPassword(h.db)
-	if err != nil {
-		if err.Error() == "неверные данные" {
-			if logErr := logs.NewLog("Токен", "control", err); logErr != nil {
-				fmt.Printf("Ошибка логирования: %s", logErr)
-			}
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "в доступе отказано"})
-			return
-		}
-		fmt.Printf("Ошибка при попытке проверить пароль: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	if err := requestData.User.Check(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := requestData.Token.GenerateToken(h.db); err != nil {
-		if logErr := logs.NewLog("Токен", "control", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-		}
-		fmt.Printf("Ошибка при попытке проверить пароль: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	var newToken Token
+	if err := newToken.GenerateToken(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Создано"})
+	c.JSON(http.StatusOK, gin.H{"message": i18n.T(c.Request.Context(), "Создано")})
 }
 
 func (h *Handler) CheckValidToken(c *gin.Context) {
-	type RequestData struct {
-		User  user.Users `json:"user"`
-		Token Token      `json:"token"`
-	}
-
-	var requestData RequestData
+	var requestData CheckTokenRequest
 
-	if err := c.ShouldBindJSON(&requestData); err != nil {
-		fmt.Printf("Ошибка при чтении данных JSON: %s", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "внутренняя ошибка сервера"})
+	if err := handlers.BindJSON(c, &requestData); err != nil {
+		c.Error(err)
 		return
 	}
 
-	access, err := requestData.Token.CheckValidToken(h.db)
+	token := Token{Token: requestData.Token.Token}
+	access, err := token.CheckValidToken(c.Request.Context(), h.db)
 	if err != nil {
-		if logErr := logs.NewLog("Токен", "control", err); logErr != nil {
-			fmt.Printf("Ошибка логирования: %s", logErr)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "ошибка при проверке токена"})
-			return
-		}
+		c.Error(err)
+		return
 	}
 
 	if access {
-		newToken, err := requestData.User.UpdateUserRole(h.db)
+		employee := user.Users{Username: requestData.User.Username}
+		newToken, err := employee.UpdateUserRole(c.Request.Context(), h.db, auth.Options(h.auth))
 		if err != nil {
-			if logErr := logs.NewLog("Токен", "control", err); err != nil {
-				fmt.Printf("Ошибка логирования: %s", logErr)
-			}
-			c.JSON(http.StatusOK, gin.H{"token": newToken})
+			c.Error(err)
 			return
 		}
+		c.JSON(http.StatusOK, gin.H{"token": newToken})
+		return
 	}
-	c.JSON(http.StatusUnauthorized, gin.H{"message": "в доступе отказано"})
+	c.Error(errInvalidToken)
+}
+
+func (h *Handler) IssueToken(c *gin.Context) {
+	var newToken Token
+	if err := newToken.GenerateToken(c.Request.Context(), h.db); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newToken)
+}
+
+// PromoteMe выдаёт роль сотрудника владельцу токена по мастер-токену
+func (h *Handler) PromoteMe(c *gin.Context) {
+	var request PromoteRequest
+	if err := handlers.BindJSON(c, &request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	token := Token{Token: request.Token}
+	access, err := token.CheckValidToken(c.Request.Context(), h.db)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if !access {
+		c.Error(errInvalidToken)
+		return
+	}
+
+	employee := user.Users{Username: auth.FromContext(c).Username}
+	newToken, err := employee.UpdateUserRole(c.Request.Context(), h.db, auth.Options(h.auth))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": newToken})
 }
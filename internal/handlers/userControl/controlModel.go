@@ -14,11 +14,11 @@ const (
 
 type Token struct {
 	Id        int    `db:"id" json:"id"`
-	Token     string `db: "token" json:"masterToken"`
-	AddedDate string `db: "addedDate" json:"addedDate"`
+	Token     string `db:"token" json:"masterToken"`
+	AddedDate string `db:"addedDate" json:"addedDate"`
 }
 
-func (t *Token) GenerateToken(db *pgxpool.Pool) error {
+func (t *Token) GenerateToken(ctx context.Context, db *pgxpool.Pool) error {
 	length := rand.Intn(5) + 4
 	b := make([]byte, length)
 	for i := range b {
@@ -27,24 +27,21 @@ func (t *Token) GenerateToken(db *pgxpool.Pool) error {
 	tokenStr := string(b)
 
 	// --
-	ctx := context.Background()
-
 	query := `
       INSERT INTO Master_Tokens(token)
       VALUES ($1)
+      RETURNING id, TO_CHAR(addedDate, 'YYYY-MM-DD HH24:MI:SS')
    `
 
-	_, err := db.Exec(ctx, query, tokenStr)
-	if err != nil {
+	if err := db.QueryRow(ctx, query, tokenStr).Scan(&t.Id, &t.AddedDate); err != nil {
 		return fmt.Errorf("ошибка при вставке токена: %w", err)
 	}
+	t.Token = tokenStr
 
 	return nil
 }
 
-func (t *Token) GetAllTokens(db *pgxpool.Pool) ([]string, error) {
-	ctx := context.Background()
-
+func (t *Token) GetAllTokens(ctx context.Context, db *pgxpool.Pool) ([]string, error) {
 	query := `
       SELECT token FROM Master_Tokens
    `
@@ -71,9 +68,7 @@ func (t *Token) GetAllTokens(db *pgxpool.Pool) ([]string, error) {
 	return tokens, nil
 }
 
-func (t *Token) CheckValidToken(db *pgxpool.Pool) (bool, error) {
-	ctx := context.Background()
-
+func (t *Token) CheckValidToken(ctx context.Context, db *pgxpool.Pool) (bool, error) {
 	query := `
 		SELECT COUNT(*) FROM Master_Tokens
 		WHERE token = $1
@@ -82,7 +77,7 @@ func (t *Token) CheckValidToken(db *pgxpool.Pool) (bool, error) {
 	var tokenCount int
 
 	if err := db.QueryRow(ctx, query, t.Token).Scan(&tokenCount); err != nil {
-		return false, fmt.Errorf("ошибка при проверке токена")
+		return false, fmt.Errorf("ошибка при проверке токена: %w", err)
 	}
 
 	if tokenCount == 0 {
@@ -96,7 +91,7 @@ func (t *Token) CheckValidToken(db *pgxpool.Pool) (bool, error) {
 
 	_, err := db.Exec(ctx, deleteQuery, t.Token)
 	if err != nil {
-		return false, fmt.Errorf("ошибка при удалении токена")
+		return false, fmt.Errorf("ошибка при удалении токена: %w", err)
 	}
 
 	return true, nil
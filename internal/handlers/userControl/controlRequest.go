@@ -0,0 +1,20 @@
+package control
+
+import "AirPort/internal/handlers/user"
+
+type GenerateTokenRequest struct {
+	User user.Credentials `json:"user"`
+}
+
+type CheckTokenRequest struct {
+	User struct {
+		Username string `json:"username" binding:"required"`
+	} `json:"user"`
+	Token struct {
+		Token string `json:"masterToken" binding:"required,max=16"`
+	} `json:"token"`
+}
+
+type PromoteRequest struct {
+	Token string `json:"masterToken" binding:"required,max=16"`
+}
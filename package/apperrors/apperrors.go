@@ -0,0 +1,188 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Code — машиночитаемый код ошибки, который клиент получает в ответе
+type Code string
+
+const (
+	CodeBadRequest   Code = "bad_request"
+	CodeValidation   Code = "validation_error"
+	CodeUnauthorized Code = "unauthorized"
+	CodeForbidden    Code = "forbidden"
+	CodeNotFound     Code = "not_found"
+	CodeConflict     Code = "conflict"
+	CodeUnavailable  Code = "unavailable"
+	CodeInternal     Code = "internal_error"
+)
+
+var statuses = map[Code]int{
+	CodeBadRequest:   http.StatusBadRequest,
+	CodeValidation:   http.StatusUnprocessableEntity,
+	CodeUnauthorized: http.StatusUnauthorized,
+	CodeForbidden:    http.StatusForbidden,
+	CodeNotFound:     http.StatusNotFound,
+	CodeConflict:     http.StatusConflict,
+	CodeUnavailable:  http.StatusServiceUnavailable,
+	CodeInternal:     http.StatusInternalServerError,
+}
+
+// Ошибки-образцы для errors.Is: сравнение идёт только по коду
+var (
+	ErrBadRequest   = &Error{Code: CodeBadRequest}
+	ErrValidation   = &Error{Code: CodeValidation}
+	ErrUnauthorized = &Error{Code: CodeUnauthorized}
+	ErrForbidden    = &Error{Code: CodeForbidden}
+	ErrNotFound     = &Error{Code: CodeNotFound}
+	ErrConflict     = &Error{Code: CodeConflict}
+	ErrUnavailable  = &Error{Code: CodeUnavailable}
+)
+
+// FieldError описывает, что не так с конкретным полем запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+
+	format string
+	args   []any
+}
+
+// NewFieldError сохраняет шаблон сообщения, чтобы его можно было перевести
+func NewFieldError(field, format string, args ...any) FieldError {
+	return FieldError{Field: field, Message: sprintf(format, args), format: format, args: args}
+}
+
+// Error — ошибка предметной области. Message и Fields показываются клиенту,
+// Err остаётся только в логах
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Err     error
+
+	format string
+	args   []any
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Message == "" && t.Fields == nil && t.Err == nil && t.Code == e.Code
+}
+
+// Wrap возвращает копию ошибки с причиной, чтобы не менять общие переменные-ошибки
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.Err = err
+	return &c
+}
+
+// WithFields возвращает копию ошибки с описанием полей
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := *e
+	c.Fields = append(append([]FieldError(nil), e.Fields...), fields...)
+	return &c
+}
+
+// WithFieldPrefix возвращает копию ошибки, где пути полей начинаются с prefix:
+// так ошибки вложенной структуры указывают на поле всего запроса
+func (e *Error) WithFieldPrefix(prefix string) *Error {
+	c := *e
+	c.Fields = make([]FieldError, len(e.Fields))
+	for i, f := range e.Fields {
+		f.Field = prefix + "." + f.Field
+		c.Fields[i] = f
+	}
+	return &c
+}
+
+// AsField превращает ошибку в ошибку поля с тем же шаблоном сообщения:
+// так ошибка одной записи попадает в общий список, не теряя перевода
+func (e *Error) AsField(field string) FieldError {
+	return FieldError{Field: field, Message: e.Message, format: e.format, args: e.args}
+}
+
+// Status возвращает HTTP статус для кода ошибки
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Code]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+func New(code Code, format string, args ...any) *Error {
+	return &Error{Code: code, Message: sprintf(format, args), format: format, args: args}
+}
+
+func BadRequest(format string, args ...any) *Error {
+	return New(CodeBadRequest, format, args...)
+}
+
+func Validation(format string, args ...any) *Error {
+	return New(CodeValidation, format, args...)
+}
+
+// Invalid — ошибка проверки с перечнем полей
+func Invalid(fields []FieldError) *Error {
+	return &Error{Code: CodeValidation, Message: "данные не прошли валидацию", Fields: fields}
+}
+
+// Field — ошибка проверки одного поля
+func Field(field, format string, args ...any) *Error {
+	return Invalid([]FieldError{NewFieldError(field, format, args...)})
+}
+
+func Unauthorized(format string, args ...any) *Error {
+	return New(CodeUnauthorized, format, args...)
+}
+
+func Forbidden(format string, args ...any) *Error {
+	return New(CodeForbidden, format, args...)
+}
+
+func NotFound(format string, args ...any) *Error {
+	return New(CodeNotFound, format, args...)
+}
+
+func Conflict(format string, args ...any) *Error {
+	return New(CodeConflict, format, args...)
+}
+
+func Unavailable(format string, args ...any) *Error {
+	return New(CodeUnavailable, format, args...)
+}
+
+// From приводит любую ошибку к Error. Всё, что не описано явно, считается
+// внутренней ошибкой, и её текст клиенту не показывается
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+
+	return &Error{Code: CodeInternal, Message: "внутренняя ошибка сервера", Err: err}
+}
+
+func sprintf(format string, args []any) string {
+	if len(args) == 0 {
+		return format
+	}
+
+	return fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,59 @@
+package apperrors
+
+import (
+	"AirPort/package/i18n"
+	"AirPort/package/requestid"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Body struct {
+	Code    Code         `json:"code"`
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// Response — единый формат ответа с ошибкой
+type Response struct {
+	Error     Body   `json:"error"`
+	RequestID string `json:"request_id"`
+}
+
+// Middleware превращает ошибку, добавленную обработчиком через c.Error,
+// в ответ с кодом, сообщением на языке запроса и ID запроса. Подробности
+// внутренних ошибок попадают только в лог запроса
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		appErr := From(c.Errors.Last().Err)
+		c.AbortWithStatusJSON(appErr.Status(), Response{
+			Error:     appErr.Localize(i18n.FromContext(c)),
+			RequestID: requestid.FromContext(c),
+		})
+	}
+}
+
+// Localize переводит сообщение и описания полей, сохраняя код ошибки
+func (e *Error) Localize(lang i18n.Lang) Body {
+	body := Body{Code: e.Code, Message: translate(lang, e.Message, e.format, e.args)}
+	for _, f := range e.Fields {
+		f.Message = translate(lang, f.Message, f.format, f.args)
+		body.Fields = append(body.Fields, f)
+	}
+
+	return body
+}
+
+// translate берёт шаблон, если он сохранён, иначе переводит готовый текст
+func translate(lang i18n.Lang, message, format string, args []any) string {
+	if format == "" {
+		return i18n.Translate(lang, message)
+	}
+
+	return i18n.Translate(lang, format, args...)
+}
@@ -0,0 +1,175 @@
+package auth
+
+import (
+	"AirPort/package/apperrors"
+	"AirPort/package/logs"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	errUnauthorized = apperrors.Unauthorized("требуется авторизация")
+	errInvalidToken = apperrors.Unauthorized("недействительный токен")
+	errForbidden    = apperrors.Forbidden("недостаточно прав")
+	errUserDeleted  = apperrors.Unauthorized("пользователь удалён")
+)
+
+// Options — параметры подписи токенов
+type Options struct {
+	JWTSecret string
+	TokenTTL  time.Duration
+}
+
+// Claims — данные пользователя в JWT. Имена полей совпадают с токенами,
+// выданными до появления пакета; токены без срока действия не принимаются
+type Claims struct {
+	UserID      int    `json:"id"`
+	Username    string `json:"username"`
+	Email       string `json:"email"`
+	Name        string `json:"name"`
+	Employee    bool   `json:"role"`
+	MasterAdmin bool   `json:"masterAdmin"`
+	Lang        string `json:"lang,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type ctxKey struct{}
+
+// Role — права пользователя. RequireEmployee и RequireMasterAdmin берут их
+// из базы, а не из токена: роль могли отозвать уже после его выдачи
+type Role struct {
+	Employee    bool
+	MasterAdmin bool
+}
+
+// RoleLookup возвращает текущие права пользователя или nil, если его удалили
+type RoleLookup func(ctx context.Context, userID int) (*Role, error)
+
+// state хранит результат разбора заголовка, чтобы Required различал
+// отсутствующий и недействительный токен
+type state struct {
+	claims *Claims
+	err    error
+	roles  RoleLookup
+}
+
+// Sign подписывает токен, который действует opts.TokenTTL с момента выдачи
+func Sign(opts Options, claims Claims) (string, error) {
+	now := time.Now()
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(opts.TokenTTL))
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(opts.JWTSecret))
+	if err != nil {
+		return "", fmt.Errorf("ошибка при создании токена: %w", err)
+	}
+
+	return token, nil
+}
+
+func Parse(secret, raw string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(raw, &claims, func(*jwt.Token) (any, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, errInvalidToken.Wrap(err)
+	}
+
+	return &claims, nil
+}
+
+// Middleware разбирает токен из заголовка Authorization, если он есть.
+// Доступ не ограничивает: для этого маршруты подключают Required и RequireEmployee,
+// которые проверяют права через roles
+func Middleware(secret string, roles RoleLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok {
+			c.Next()
+			return
+		}
+
+		claims, err := Parse(secret, strings.TrimSpace(raw))
+		if claims != nil {
+			logs.SetUserID(c, claims.UserID)
+		}
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ctxKey{}, state{claims: claims, err: err, roles: roles}))
+
+		c.Next()
+	}
+}
+
+// FromContext возвращает данные пользователя или nil для анонимного запроса
+func FromContext(ctx context.Context) *Claims {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	s, _ := ctx.Value(ctxKey{}).(state)
+
+	return s.claims
+}
+
+// Language отдаёт язык из токена для i18n.Middleware
+func Language(c *gin.Context) string {
+	if claims := FromContext(c); claims != nil {
+		return claims.Lang
+	}
+
+	return ""
+}
+
+// Required пропускает только запросы с действительным токеном
+func Required() gin.HandlerFunc {
+	return require(nil)
+}
+
+// RequireEmployee пропускает сотрудников и мастер-администраторов
+func RequireEmployee() gin.HandlerFunc {
+	return require(func(role Role) bool {
+		return role.Employee || role.MasterAdmin
+	})
+}
+
+func RequireMasterAdmin() gin.HandlerFunc {
+	return require(func(role Role) bool {
+		return role.MasterAdmin
+	})
+}
+
+func require(allowed func(role Role) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s, _ := c.Request.Context().Value(ctxKey{}).(state)
+		switch {
+		case s.err != nil:
+			abort(c, s.err)
+			return
+		case s.claims == nil:
+			abort(c, errUnauthorized)
+			return
+		case allowed == nil:
+			return
+		}
+
+		role, err := s.roles(c.Request.Context(), s.claims.UserID)
+		switch {
+		case err != nil:
+			abort(c, fmt.Errorf("ошибка при проверке прав: %w", err))
+		case role == nil:
+			abort(c, errUserDeleted)
+		case !allowed(*role):
+			abort(c, errForbidden)
+		}
+	}
+}
+
+// abort останавливает цепочку, а ответ формирует apperrors.Middleware
+func abort(c *gin.Context, err error) {
+	c.Error(err)
+	c.Abort()
+}
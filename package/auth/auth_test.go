@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"AirPort/package/apperrors"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const secret = "secret"
+
+func TestParse(t *testing.T) {
+	valid, err := Sign(Options{JWTSecret: secret, TokenTTL: time.Hour}, Claims{UserID: 1})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	expired, err := Sign(Options{JWTSecret: secret, TokenTTL: -time.Minute}, Claims{UserID: 1})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	// Так выглядели токены до появления срока действия
+	unlimited, err := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{UserID: 1, Employee: true}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"действующий токен", valid, true},
+		{"истёкший токен", expired, false},
+		{"токен без срока действия", unlimited, false},
+		{"чужая подпись", valid[:len(valid)-2] + "xx", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := Parse(secret, tt.token)
+			if tt.ok {
+				if err != nil || claims.UserID != 1 {
+					t.Fatalf("ожидался пользователь 1, получено %+v, ошибка %v", claims, err)
+				}
+				if claims.ExpiresAt == nil || claims.IssuedAt == nil {
+					t.Errorf("в токене нет времени выдачи или окончания: %+v", claims.RegisteredClaims)
+				}
+				return
+			}
+			if !errors.Is(err, apperrors.ErrUnauthorized) {
+				t.Errorf("ожидалась ошибка авторизации, получено %v", err)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Токен выдан, когда пользователь ещё был мастер-администратором
+	token, err := Sign(Options{JWTSecret: secret, TokenTTL: time.Hour}, Claims{UserID: 1, Employee: true, MasterAdmin: true})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		role     *Role
+		lookup   error
+		employee int
+		master   int
+		required int
+	}{
+		{"мастер-администратор", &Role{Employee: true, MasterAdmin: true}, nil, http.StatusOK, http.StatusOK, http.StatusOK},
+		{"права мастер-администратора отозваны", &Role{Employee: true}, nil, http.StatusOK, http.StatusForbidden, http.StatusOK},
+		{"роль сотрудника отозвана", &Role{}, nil, http.StatusForbidden, http.StatusForbidden, http.StatusOK},
+		{"пользователь удалён", nil, nil, http.StatusUnauthorized, http.StatusUnauthorized, http.StatusOK},
+		{"ошибка базы данных", nil, errors.New("connection refused"), http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles := func(_ context.Context, userID int) (*Role, error) {
+				if userID != 1 {
+					t.Errorf("права запрошены для пользователя %d", userID)
+				}
+				return tt.role, tt.lookup
+			}
+
+			router := gin.New()
+			router.Use(apperrors.Middleware(), Middleware(secret, roles))
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET("/employee", RequireEmployee(), ok)
+			router.GET("/master", RequireMasterAdmin(), ok)
+			router.GET("/me", Required(), ok)
+
+			for path, want := range map[string]int{"/employee": tt.employee, "/master": tt.master, "/me": tt.required} {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, req)
+
+				if w.Code != want {
+					t.Errorf("%s: статус %d, ожидался %d", path, w.Code, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireWithoutToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	roles := func(context.Context, int) (*Role, error) {
+		t.Error("права не должны запрашиваться без токена")
+		return nil, nil
+	}
+
+	router := gin.New()
+	router.Use(apperrors.Middleware(), Middleware(secret, roles))
+	router.GET("/employee", RequireEmployee(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/employee", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("статус %d, ожидался %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,80 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+type call[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Cache хранит значения ограниченное время. Одновременные запросы одного ключа
+// ждут единственной загрузки, а не идут в источник каждый сам по себе
+type Cache[T any] struct {
+	ttl time.Duration
+
+	mu       sync.Mutex
+	entries  map[string]entry[T]
+	inFlight map[string]*call[T]
+}
+
+func New[T any](ttl time.Duration) *Cache[T] {
+	return &Cache[T]{
+		ttl:      ttl,
+		entries:  make(map[string]entry[T]),
+		inFlight: make(map[string]*call[T]),
+	}
+}
+
+// Get возвращает значение из кэша или вызывает load. Ошибки не кэшируются
+func (c *Cache[T]) Get(key string, load func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && time.Now().Before(e.expires) {
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	if cl, ok := c.inFlight[key]; ok {
+		c.mu.Unlock()
+		<-cl.done
+		return cl.value, cl.err
+	}
+
+	cl := &call[T]{done: make(chan struct{})}
+	c.inFlight[key] = cl
+	c.mu.Unlock()
+
+	cl.value, cl.err = load()
+
+	c.mu.Lock()
+	delete(c.inFlight, key)
+	if cl.err == nil {
+		c.entries[key] = entry[T]{value: cl.value, expires: time.Now().Add(c.ttl)}
+	}
+	c.removeExpired()
+	c.mu.Unlock()
+	close(cl.done)
+
+	return cl.value, cl.err
+}
+
+// TTL возвращает время жизни записей
+func (c *Cache[T]) TTL() time.Duration {
+	return c.ttl
+}
+
+func (c *Cache[T]) removeExpired() {
+	now := time.Now()
+	for key, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package csvtable
+
+import (
+	"AirPort/package/apperrors"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Table читает CSV с заголовком. Колонки ищутся по имени без учёта регистра,
+// порядок колонок в файле не важен
+type Table struct {
+	reader *csv.Reader
+	index  map[string]int
+	record []string
+}
+
+// New читает заголовок и проверяет обязательные колонки: для каждой
+// достаточно одного из её имён, в ошибке называется первое
+func New(r io.Reader, required ...[]string) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, apperrors.BadRequest("файл импорта пуст")
+	}
+	if err != nil {
+		return nil, apperrors.BadRequest("не удалось прочитать заголовок CSV").Wrap(err)
+	}
+	if len(header) == 1 && strings.Contains(header[0], ";") {
+		return nil, apperrors.BadRequest("разделитель «;» не поддерживается, используйте запятую")
+	}
+
+	t := &Table{reader: reader, index: make(map[string]int, len(header))}
+	for i, name := range header {
+		t.index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, names := range required {
+		if !t.Has(names...) {
+			return nil, apperrors.BadRequest("в CSV нет колонки %s", names[0])
+		}
+	}
+
+	return t, nil
+}
+
+func (t *Table) Has(names ...string) bool {
+	for _, name := range names {
+		if _, ok := t.index[strings.ToLower(name)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Next читает следующую запись и возвращает номер её строки, io.EOF в конце файла
+func (t *Table) Next() (int, error) {
+	record, err := t.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	if err != nil {
+		// FieldPos после ошибки чтения не работает, строка есть только в ParseError
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return 0, apperrors.BadRequest("ошибка чтения CSV в строке %d", parseErr.Line).Wrap(err)
+		}
+		return 0, apperrors.BadRequest("ошибка чтения CSV").Wrap(err)
+	}
+	t.record = record
+	line, _ := t.reader.FieldPos(0)
+
+	return line, nil
+}
+
+// Value возвращает значение колонки текущей записи без пробелов по краям.
+// Нет колонки или она не заполнена в короткой строке — пустая строка
+func (t *Table) Value(name string) string {
+	i, ok := t.index[strings.ToLower(name)]
+	if !ok || i >= len(t.record) {
+		return ""
+	}
+
+	return strings.TrimSpace(t.record[i])
+}
@@ -1,27 +1,134 @@
 package database
 
 import (
-	"AirPort/internal/config"
+	"AirPort/package/tracing"
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
-func OpenDBClient(ctx context.Context, config config.StorageConfig) (*pgxpool.Pool, error) {
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		config.Host, config.Port, config.Username, config.Password, config.Database)
+const (
+	connectBackoffMin = 500 * time.Millisecond
+	connectBackoffMax = 10 * time.Second
+)
+
+// Options — адрес и учётные данные PostgreSQL, TLS, размер пула и таймауты подключения
+type Options struct {
+	Host     string
+	Port     string
+	Database string
+	Username string
+	Password string
+
+	SSLMode     string
+	SSLRootCert string
+	SSLCert     string
+	SSLKey      string
+
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	StatementTimeout  time.Duration
+
+	ConnectTimeout time.Duration
+	ConnectMaxWait time.Duration
+}
+
+// OpenDBClient создаёт пул и ждёт базу данных до ConnectMaxWait, повторяя
+// попытки с растущей паузой, чтобы сервис мог стартовать раньше Postgres
+func OpenDBClient(ctx context.Context, opts Options) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(connString(opts))
+	if err != nil {
+		return nil, fmt.Errorf("Ошибка в параметрах подключения к базе данных: %s", err)
+	}
+
+	poolConfig.MaxConns = opts.MaxConns
+	poolConfig.MinConns = opts.MinConns
+	poolConfig.MaxConnLifetime = opts.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = opts.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = opts.HealthCheckPeriod
+	poolConfig.LazyConnect = true
+	if opts.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	// Логгер pgx используется только для спанов SQL запросов
+	poolConfig.ConnConfig.Logger = tracing.QueryLogger()
+	poolConfig.ConnConfig.LogLevel = pgx.LogLevelInfo
 
-	pool, err := pgxpool.Connect(ctx, connStr)
+	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("Ошибка при подключении к базе данных: %s", err)
 	}
 
-	if err := pool.Ping(ctx); err != nil {
-		return nil, fmt.Errorf("Попытка соединения не удалась: %s", err)
+	if err := waitForDB(ctx, pool, opts); err != nil {
+		pool.Close()
+		return nil, err
 	}
 
 	log.Println("\033[32mПодключено к бд\033[0m")
 	return pool, nil
 }
+
+func waitForDB(ctx context.Context, pool *pgxpool.Pool, opts Options) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.ConnectMaxWait)
+	defer cancel()
+
+	backoff := connectBackoffMin
+	for attempt := 1; ; attempt++ {
+		pingCtx, cancelPing := context.WithTimeout(ctx, opts.ConnectTimeout)
+		err := pool.Ping(pingCtx)
+		cancelPing()
+		if err == nil {
+			return nil
+		}
+
+		log.Printf("Попытка %d подключения к базе данных не удалась: %s", attempt, err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Попытка соединения не удалась: %s", err)
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, connectBackoffMax)
+	}
+}
+
+func connString(opts Options) string {
+	query := url.Values{}
+	query.Set("sslmode", opts.SSLMode)
+	query.Set("connect_timeout", strconv.Itoa(max(int(opts.ConnectTimeout.Seconds()), 1)))
+	if opts.SSLRootCert != "" {
+		query.Set("sslrootcert", opts.SSLRootCert)
+	}
+	if opts.SSLCert != "" {
+		query.Set("sslcert", opts.SSLCert)
+	}
+	if opts.SSLKey != "" {
+		query.Set("sslkey", opts.SSLKey)
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(opts.Username, opts.Password),
+		Host:     net.JoinHostPort(opts.Host, opts.Port),
+		Path:     "/" + opts.Database,
+		RawQuery: query.Encode(),
+	}
+
+	return dsn.String()
+}
+
+// Querier — общее у пула и транзакции, чтобы одна функция работала и там, и там
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
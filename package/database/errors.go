@@ -0,0 +1,27 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgconn"
+)
+
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// IsUniqueViolation сообщает, что запись нарушила уникальный индекс
+func IsUniqueViolation(err error) bool {
+	return hasCode(err, uniqueViolation)
+}
+
+// IsForeignKeyViolation сообщает, что запись ссылается на несуществующую строку
+func IsForeignKeyViolation(err error) bool {
+	return hasCode(err, foreignKeyViolation)
+}
+
+func hasCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type Migration struct {
+	Version string
+	SQL     string
+}
+
+func loadMigrations() ([]Migration, error) {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при чтении миграций: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		data, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при чтении миграции %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{
+			Version: strings.TrimSuffix(entry.Name(), ".sql"),
+			SQL:     string(data),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+func createMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
+	query := `
+		CREATE TABLE IF NOT EXISTS Schema_Migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT NOW()
+		)
+	`
+
+	if _, err := pool.Exec(ctx, query); err != nil {
+		return fmt.Errorf("ошибка при создании таблицы миграций: %w", err)
+	}
+
+	return nil
+}
+
+// appliedMigrations только читает БД, поэтому её можно вызывать из проверки готовности.
+// Пока таблицы миграций нет, не применена ни одна миграция
+func appliedMigrations(ctx context.Context, pool *pgxpool.Pool) (map[string]bool, error) {
+	applied := make(map[string]bool)
+
+	var exists bool
+	if err := pool.QueryRow(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("ошибка при получении применённых миграций: %w", err)
+	}
+	if !exists {
+		return applied, nil
+	}
+
+	rows, err := pool.Query(ctx, `SELECT version FROM Schema_Migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при получении применённых миграций: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("ошибка при получении применённых миграций: %w", err)
+		}
+		applied[version] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка при получении применённых миграций: %w", err)
+	}
+
+	return applied, nil
+}
+
+// Migrate применяет все ещё не применённые миграции, каждую в своей транзакции
+func Migrate(ctx context.Context, pool *pgxpool.Pool) ([]string, error) {
+	if err := createMigrationsTable(ctx, pool); err != nil {
+		return nil, err
+	}
+
+	pending, err := PendingMigrations(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, m := range pending {
+		tx, err := pool.Begin(ctx)
+		if err != nil {
+			return done, fmt.Errorf("ошибка при начале транзакции миграции: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, m.SQL); err != nil {
+			tx.Rollback(ctx)
+			return done, fmt.Errorf("ошибка при применении миграции %s: %w", m.Version, err)
+		}
+
+		if _, err := tx.Exec(ctx, `INSERT INTO Schema_Migrations (version) VALUES ($1)`, m.Version); err != nil {
+			tx.Rollback(ctx)
+			return done, fmt.Errorf("ошибка при записи миграции %s: %w", m.Version, err)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return done, fmt.Errorf("ошибка при фиксации миграции %s: %w", m.Version, err)
+		}
+		done = append(done, m.Version)
+	}
+
+	return done, nil
+}
+
+func PendingMigrations(ctx context.Context, pool *pgxpool.Pool) ([]Migration, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := appliedMigrations(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+// CheckMigrations возвращает ошибку, если в БД применены не все миграции
+func CheckMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	pending, err := PendingMigrations(ctx, pool)
+	if err != nil {
+		return err
+	}
+	if len(pending) != 0 {
+		return fmt.Errorf("не применено миграций: %d", len(pending))
+	}
+
+	return nil
+}
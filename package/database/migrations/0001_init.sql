@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS Users (
+	id SERIAL PRIMARY KEY,
+	username VARCHAR(255) NOT NULL UNIQUE,
+	name VARCHAR(255) NOT NULL DEFAULT '',
+	password VARCHAR(255) NOT NULL,
+	email VARCHAR(255) NOT NULL UNIQUE,
+	userRole BOOLEAN NOT NULL DEFAULT FALSE,
+	masterAdmin BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS Board (
+	id SERIAL PRIMARY KEY,
+	flightNumber VARCHAR(6) NOT NULL,
+	appointment VARCHAR(255) NOT NULL,
+	departure TIMESTAMP NOT NULL,
+	status VARCHAR(50) NOT NULL DEFAULT 'Регистрация',
+	status_change_time TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS Tickets (
+	id SERIAL PRIMARY KEY,
+	userId INTEGER NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+	flightId INTEGER NOT NULL REFERENCES Board(id) ON DELETE CASCADE,
+	seatNumber VARCHAR(3) NOT NULL,
+	price INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS Notifications (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES Users(id) ON DELETE CASCADE,
+	ticket_id INTEGER NOT NULL REFERENCES Tickets(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS Master_Tokens (
+	id SERIAL PRIMARY KEY,
+	token VARCHAR(16) NOT NULL,
+	addedDate TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS Report_Jobs (
+	id VARCHAR(32) PRIMARY KEY,
+	params JSONB NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	report_id VARCHAR(32) NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	started_at TIMESTAMP,
+	finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS report_jobs_created_at_idx ON Report_Jobs (created_at DESC);
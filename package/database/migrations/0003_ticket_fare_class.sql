@@ -0,0 +1 @@
+ALTER TABLE Tickets ADD COLUMN IF NOT EXISTS fareClass VARCHAR(20) NOT NULL DEFAULT 'economy';
@@ -0,0 +1,2 @@
+ALTER TABLE Board ADD COLUMN IF NOT EXISTS actual_departure TIMESTAMP;
+ALTER TABLE Board ADD COLUMN IF NOT EXISTS seat_capacity INTEGER NOT NULL DEFAULT 63;
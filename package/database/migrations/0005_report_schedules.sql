@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS Report_Schedules (
+	id SERIAL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	cron VARCHAR(100) NOT NULL,
+	params JSONB NOT NULL,
+	emails TEXT[] NOT NULL DEFAULT '{}',
+	webhook_url TEXT NOT NULL DEFAULT '',
+	enabled BOOLEAN NOT NULL DEFAULT TRUE,
+	next_run TIMESTAMP NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS report_schedules_next_run_idx ON Report_Schedules (next_run) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS Report_Schedule_Runs (
+	id SERIAL PRIMARY KEY,
+	schedule_id INTEGER NOT NULL REFERENCES Report_Schedules(id) ON DELETE CASCADE,
+	request_id VARCHAR(128) NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	report_id VARCHAR(32) NOT NULL DEFAULT '',
+	started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+	finished_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS report_schedule_runs_schedule_idx ON Report_Schedule_Runs (schedule_id, started_at DESC);
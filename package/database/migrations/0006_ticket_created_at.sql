@@ -0,0 +1,7 @@
+-- Дата покупки уже проданных билетов неизвестна: колонка добавляется без значения
+-- по умолчанию, чтобы они остались с NULL, а NOW() получали только новые билеты
+ALTER TABLE Tickets ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+ALTER TABLE Tickets ALTER COLUMN created_at SET DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS tickets_created_at_idx ON Tickets (created_at);
+CREATE INDEX IF NOT EXISTS board_departure_idx ON Board (departure);
@@ -0,0 +1,2 @@
+ALTER TABLE Users ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT 'ru';
+ALTER TABLE Report_Schedules ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT 'ru';
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS Flight_Schedules (
+	id SERIAL PRIMARY KEY,
+	flight_number VARCHAR(6) NOT NULL,
+	origin VARCHAR(100) NOT NULL,
+	destination VARCHAR(255) NOT NULL,
+	days_of_week INTEGER[] NOT NULL,
+	departure_time TIME NOT NULL,
+	arrival_time TIME,
+	valid_from DATE NOT NULL,
+	valid_to DATE,
+	aircraft_type VARCHAR(10) NOT NULL DEFAULT '',
+	seat_capacity INTEGER NOT NULL DEFAULT 63,
+	created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Дни, на которые рейс по расписанию уже создан. Удалённый с табло рейс
+-- оставляет день с пустым board_id, чтобы генератор не создал его снова
+CREATE TABLE IF NOT EXISTS Flight_Schedule_Days (
+	schedule_id INTEGER NOT NULL REFERENCES Flight_Schedules(id) ON DELETE CASCADE,
+	flight_date DATE NOT NULL,
+	board_id INTEGER REFERENCES Board(id) ON DELETE SET NULL,
+	edited BOOLEAN NOT NULL DEFAULT FALSE,
+	PRIMARY KEY (schedule_id, flight_date)
+);
+
+CREATE INDEX IF NOT EXISTS flight_schedule_days_board_idx ON Flight_Schedule_Days (board_id);
+
+ALTER TABLE Board ADD COLUMN IF NOT EXISTS aircraft_type VARCHAR(10) NOT NULL DEFAULT '';
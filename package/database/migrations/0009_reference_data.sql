@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS Airports (
+	iata VARCHAR(3) PRIMARY KEY,
+	icao VARCHAR(4) NOT NULL DEFAULT '',
+	name VARCHAR(255) NOT NULL,
+	city VARCHAR(255) NOT NULL DEFAULT '',
+	country VARCHAR(2) NOT NULL DEFAULT '',
+	timezone VARCHAR(64) NOT NULL DEFAULT '',
+	latitude DOUBLE PRECISION,
+	longitude DOUBLE PRECISION,
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS airports_icao_idx ON Airports (icao);
+CREATE INDEX IF NOT EXISTS airports_city_idx ON Airports (LOWER(city));
+
+CREATE TABLE IF NOT EXISTS Airlines (
+	iata VARCHAR(2) PRIMARY KEY,
+	icao VARCHAR(3) NOT NULL DEFAULT '',
+	name VARCHAR(255) NOT NULL,
+	country VARCHAR(100) NOT NULL DEFAULT '',
+	updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS airlines_icao_idx ON Airlines (icao);
+
+-- Код аэропорта назначения; у старых рейсов остаётся только текст appointment
+ALTER TABLE Board ADD COLUMN IF NOT EXISTS destination_code VARCHAR(3) REFERENCES Airports(iata);
+
+CREATE INDEX IF NOT EXISTS board_destination_code_idx ON Board (destination_code);
@@ -0,0 +1,12 @@
+-- Номер рейса уникален в пределах даты вылета. Проверка в приложении не защищает
+-- от одновременного создания, поэтому правило закрепляется индексом
+DO $$
+BEGIN
+	IF EXISTS (
+		SELECT 1 FROM Board GROUP BY flightNumber, departure::date HAVING COUNT(*) > 1
+	) THEN
+		RAISE EXCEPTION 'в Board есть рейсы с одинаковым номером в одну дату, удалите дубликаты перед миграцией';
+	END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS board_flight_date_idx ON Board (flightNumber, (departure::date));
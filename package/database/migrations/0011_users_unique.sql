@@ -0,0 +1,18 @@
+-- В базах, созданных до 0001_init, таблица Users уже была, и UNIQUE из CREATE TABLE
+-- в них не попал. Ограничения получают те же имена, что создаёт 0001_init в новой базе
+DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'users'::regclass AND conname = 'users_username_key') THEN
+		IF EXISTS (SELECT 1 FROM Users GROUP BY username HAVING COUNT(*) > 1) THEN
+			RAISE EXCEPTION 'в Users есть повторяющиеся username, исправьте их перед миграцией';
+		END IF;
+		ALTER TABLE Users ADD CONSTRAINT users_username_key UNIQUE (username);
+	END IF;
+
+	IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'users'::regclass AND conname = 'users_email_key') THEN
+		IF EXISTS (SELECT 1 FROM Users GROUP BY email HAVING COUNT(*) > 1) THEN
+			RAISE EXCEPTION 'в Users есть повторяющиеся email, исправьте их перед миграцией';
+		END IF;
+		ALTER TABLE Users ADD CONSTRAINT users_email_key UNIQUE (email);
+	END IF;
+END $$;
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	readAttempts = 3
+	readBackoff  = 100 * time.Millisecond
+)
+
+// RetryRead повторяет идемпотентное чтение, если оно упало из-за обрыва
+// соединения или перезапуска Postgres. Для записи не подходит: повтор может
+// выполнить изменение дважды
+func RetryRead[T any](ctx context.Context, db *pgxpool.Pool, read func(context.Context, *pgxpool.Pool) (T, error)) (T, error) {
+	backoff := readBackoff
+
+	for attempt := 1; ; attempt++ {
+		result, err := read(ctx, db)
+		if err == nil || attempt == readAttempts || !IsTransient(err) {
+			return result, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// IsTransient сообщает, что ошибка связана с соединением, а не с самим запросом
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		// 08 — ошибки соединения, 57P01-57P03 — остановка или запуск сервера
+		case len(pgErr.Code) == 5 && pgErr.Code[:2] == "08",
+			pgErr.Code == "57P01", pgErr.Code == "57P02", pgErr.Code == "57P03":
+			return true
+		}
+		return false
+	}
+
+	if pgconn.SafeToRetry(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
@@ -0,0 +1,51 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+var errPrivateAddress = errors.New("адрес webhook во внутренней сети запрещён")
+
+// CheckHost отклоняет localhost и IP-адреса внутренних сетей. Имена хостов
+// проверяются повторно при подключении, когда известен их адрес
+func CheckHost(host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errPrivateAddress
+	}
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil && !public(addr) {
+		return errPrivateAddress
+	}
+
+	return nil
+}
+
+func public(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	return !(addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() || addr.IsUnspecified())
+}
+
+// dialPublic не даёт подключиться к внутреннему адресу, в том числе после
+// перенаправления или если имя хоста разрешилось во внутреннюю сеть
+func dialPublic(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("неверный адрес webhook %s: %w", address, err)
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("неверный адрес webhook %s: %w", address, err)
+	}
+	if !public(addr) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, addr)
+	}
+
+	return nil
+}
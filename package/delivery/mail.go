@@ -0,0 +1,215 @@
+package delivery
+
+import (
+	"AirPort/package/requestid"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+const (
+	smtpDialTimeout = 10 * time.Second
+	// smtpTimeout ограничивает отправку, если у контекста нет своего срока
+	smtpTimeout = time.Minute
+)
+
+var errSMTPNotConfigured = errors.New("SMTP сервер не настроен")
+
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// SMTPOptions — сервер и отправитель писем; без Host письма не отправляются
+type SMTPOptions struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type Mailer struct {
+	cfg SMTPOptions
+}
+
+func NewMailer(cfg SMTPOptions) *Mailer {
+	return &Mailer{cfg: cfg}
+}
+
+// Send отправляет письмо; ID запроса из контекста попадает в заголовок X-Request-ID
+func (m *Mailer) Send(ctx context.Context, msg Message) error {
+	if m.cfg.Host == "" {
+		return errSMTPNotConfigured
+	}
+
+	data, err := m.build(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	if err := m.send(ctx, auth, msg.To, data); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return fmt.Errorf("ошибка при отправке письма: %w", err)
+	}
+
+	return nil
+}
+
+// send повторяет smtp.SendMail, но подключается с учётом контекста и ограничивает
+// время всего диалога, чтобы зависший SMTP сервер не держал планировщик отчётов
+func (m *Mailer) send(ctx context.Context, auth smtp.Auth, to []string, data []byte) error {
+	dialer := net.Dialer{Timeout: smtpDialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.cfg.Host, m.cfg.Port))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Отмена контекста прерывает диалог, не дожидаясь срока
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, m.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			return errors.New("SMTP сервер не поддерживает авторизацию")
+		}
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.cfg.From); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := client.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (m *Mailer) build(ctx context.Context, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + m.cfg.From,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.BEncoding.Encode("utf-8", msg.Subject),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + writer.Boundary(),
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		headers = append(headers, requestid.Header+": "+id)
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	body, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при формировании письма: %w", err)
+	}
+	qp := quotedprintable.NewWriter(body)
+	if _, err := qp.Write([]byte(msg.Body)); err != nil {
+		return nil, fmt.Errorf("ошибка при формировании письма: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка при формировании письма: %w", err)
+	}
+
+	for _, attachment := range msg.Attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ошибка при добавлении вложения: %w", err)
+		}
+		if err := writeBase64Lines(part, attachment.Data); err != nil {
+			return nil, fmt.Errorf("ошибка при добавлении вложения: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("ошибка при формировании письма: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines пишет base64 строками по 76 символов, как требует RFC 2045
+func writeBase64Lines(w io.Writer, data []byte) error {
+	const lineLength = 76
+
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		n := min(lineLength, len(encoded))
+		if _, err := w.Write([]byte(encoded[:n] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package delivery
+
+import (
+	"AirPort/package/requestid"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const webhookTimeout = 30 * time.Second
+
+type Webhook struct {
+	client *http.Client
+}
+
+// NewWebhook создаёт клиент, который ходит только на публичные адреса: адрес webhook
+// задаёт пользователь, и отчёт не должен уйти во внутреннюю сеть
+func NewWebhook() *Webhook {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, Control: dialPublic}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	return &Webhook{client: &http.Client{Timeout: webhookTimeout, Transport: transport}}
+}
+
+// Post отправляет метаданные и файл одним multipart запросом
+func (w *Webhook) Post(ctx context.Context, url string, metadata any, attachment Attachment) error {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	meta, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+	metaPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {`form-data; name="metadata"`},
+		"Content-Type":        {"application/json"},
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+	if _, err := metaPart.Write(meta); err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+
+	filePart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {fmt.Sprintf(`form-data; name="file"; filename=%q`, attachment.Name)},
+		"Content-Type":        {attachment.ContentType},
+	})
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+	if _, err := filePart.Write(attachment.Data); err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &buf)
+	if err != nil {
+		return fmt.Errorf("ошибка при формировании webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ошибка при отправке webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook ответил статусом %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var errShuttingDown = errors.New("сервер останавливается")
+
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker собирает проверки готовности: БД, миграции, фоновые обработчики
+type Checker struct {
+	mu     sync.RWMutex
+	checks []check
+
+	shuttingDown atomic.Bool
+}
+
+func New() *Checker {
+	return &Checker{}
+}
+
+func (h *Checker) AddCheck(name string, fn CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checks = append(h.checks, check{name: name, fn: fn})
+}
+
+// SetShuttingDown переводит готовность в состояние ошибки, чтобы балансировщик снял трафик
+func (h *Checker) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Ready выполняет все проверки и возвращает результат каждой из них
+func (h *Checker) Ready(ctx context.Context) (map[string]string, bool) {
+	h.mu.RLock()
+	checks := append([]check(nil), h.checks...)
+	h.mu.RUnlock()
+
+	results := make(map[string]string, len(checks)+1)
+	ready := true
+
+	if h.shuttingDown.Load() {
+		results["shutdown"] = errShuttingDown.Error()
+		ready = false
+	}
+
+	for _, c := range checks {
+		if err := c.fn(ctx); err != nil {
+			results[c.name] = err.Error()
+			ready = false
+			continue
+		}
+		results[c.name] = "ok"
+	}
+
+	return results, ready
+}
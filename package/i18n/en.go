@@ -0,0 +1,123 @@
+package i18n
+
+var en = map[string]string{
+	// Общие ошибки
+	"внутренняя ошибка сервера":  "internal server error",
+	"маршрут не найден":          "route not found",
+	"некорректный JSON":          "malformed JSON",
+	"данные не прошли валидацию": "validation failed",
+	"ожидается тип %s":           "expected type %s",
+	"недопустимое значение":      "invalid value",
+
+	// Проверка полей
+	"обязательное поле":                       "field is required",
+	"неверный формат email":                   "invalid email format",
+	"неверный адрес":                          "invalid URL",
+	"допустимые значения: %s":                 "allowed values: %s",
+	"значение должно быть не меньше %s":       "value must be at least %s",
+	"значение должно быть не больше %s":       "value must be at most %s",
+	"длина должна быть не меньше %s символов": "length must be at least %s characters",
+	"длина должна быть не больше %s символов": "length must be at most %s characters",
+	"нужно не меньше %s элементов":            "at least %s items required",
+	"нужно не больше %s элементов":            "at most %s items allowed",
+	"значение должно быть больше %s":          "value must be greater than %s",
+	"длина должна быть %s":                    "length must be %s",
+	"неверный формат, ожидается %s":           "invalid format, expected %s",
+	"дата должна быть в будущем":              "date must be in the future",
+	"неверный номер рейса, ожидается код авиакомпании и до 4 цифр, например SU1234": "invalid flight number, expected an airline code and up to 4 digits, e.g. SU1234",
+	"неверный код авиакомпании IATA, ожидается 2 символа":                           "invalid IATA airline code, expected 2 characters",
+	"неверный код аэропорта IATA, ожидается 3 буквы":                                "invalid IATA airport code, expected 3 letters",
+	"неверный код аэропорта ICAO, ожидается 4 символа":                              "invalid ICAO airport code, expected 4 characters",
+	"неверный код авиакомпании ICAO, ожидается 3 буквы":                             "invalid ICAO airline code, expected 3 letters",
+	"пароль должен быть от %d до %d символов и содержать буквы и цифры":             "password must be %d to %d characters long and contain letters and digits",
+
+	// Пользователи и токены
+	"неверный логин или пароль":                              "invalid username or password",
+	"пользователь с таким username уже существует":           "username is already taken",
+	"пользователь с таким email уже существует":              "email is already registered",
+	"пользователь с таким username или email уже существует": "username or email is already taken",
+	"пользователь не найден":                                 "user not found",
+	"недействительный мастер-токен":                          "invalid master token",
+	"требуется авторизация":                                  "authentication required",
+	"недействительный токен":                                 "invalid token",
+	"недостаточно прав":                                      "insufficient permissions",
+	"пользователь удалён":                                    "user has been deleted",
+
+	// Рейсы и билеты
+	"рейс не найден":                      "flight not found",
+	"такой рейс уже существует":           "flight already exists",
+	"пользователь или рейс не найден":     "user or flight not found",
+	"неизвестный класс обслуживания: %s":  "unknown fare class: %s",
+	"limit должен быть от 1 до %d":        "limit must be between 1 and %d",
+	"Оформлен билет на рейс %s, место %s": "Ticket issued for flight %s, seat %s",
+
+	// Импорт расписания
+	"должно быть целым числом":                                          "must be an integer",
+	"файл импорта пуст":                                                 "import file is empty",
+	"не удалось прочитать заголовок CSV":                                "failed to read CSV header",
+	"разделитель «;» не поддерживается, используйте запятую":            "\";\" delimiter is not supported, use a comma",
+	"в CSV нет колонки %s":                                              "CSV has no %s column",
+	"ошибка чтения CSV в строке %d":                                     "CSV read error on line %d",
+	"запись SSIM короче %d символов":                                    "SSIM record is shorter than %d characters",
+	"неверная дата начала периода: %s":                                  "invalid period start date: %s",
+	"неверная дата окончания периода: %s":                               "invalid period end date: %s",
+	"неверное время отправления: %s":                                    "invalid departure time: %s",
+	"неверное смещение от UTC аэропорта вылета: %s":                     "invalid UTC offset of the departure station: %s",
+	"не задан горизонт импорта SSIM":                                    "SSIM import horizon is not set",
+	"расписание SSIM даёт больше %d рейсов, уменьшите горизонт импорта": "SSIM schedule expands to more than %d flights, reduce the import horizon",
+	"ошибка чтения файла SSIM":                                          "failed to read SSIM file",
+	"в файле нет рейсов для импорта":                                    "file contains no flights to import",
+	"в файле больше %d рейсов, разделите его на части":                  "file contains more than %d flights, split it into parts",
+	"файл импорта содержит ошибки, рейсы не добавлены":                  "import file contains errors, no flights were added",
+	"файл импорта больше %d МБ":                                         "import file is larger than %d MB",
+
+	// Расписания рейсов
+	"расписание рейса не найдено": "flight schedule not found",
+
+	// Справочники аэропортов и авиакомпаний
+	"аэропорт не найден":                                    "airport not found",
+	"авиакомпания не найдена":                               "airline not found",
+	"аэропорт %s не найден в справочнике":                   "airport %s not found in the directory",
+	"должно быть числом":                                    "must be a number",
+	"в файле нет записей для загрузки":                      "file contains no records to load",
+	"в файле больше %d записей, разделите его на части":     "file contains more than %d records, split it into parts",
+	"файл справочника содержит ошибки, данные не загружены": "directory file contains errors, nothing was loaded",
+	"файл справочника больше %d МБ":                         "directory file is larger than %d MB",
+
+	// Статусы рейсов
+	"Регистрация": "Check-in",
+	"Посадка":     "Boarding",
+	"Задержан":    "Delayed",
+	"Отменён":     "Cancelled",
+	"Вылетел":     "Departed",
+	"Прибыл":      "Arrived",
+
+	// Отчёты
+	"задача не найдена":                            "job not found",
+	"задача уже завершена":                         "job is already finished",
+	"отчёт не найден":                              "report not found",
+	"отчёт ещё не готов":                           "report is not ready yet",
+	"расписание не найдено":                        "schedule not found",
+	"очередь отчётов переполнена, повторите позже": "report queue is full, try again later",
+	"неподдерживаемый формат отчёта: %s":           "unsupported report format: %s",
+	"неизвестный тип отчёта: %s":                   "unknown report type: %s",
+	"фильтр доступен только для отчёта о продажах": "filter is only available for the sales report",
+	"нельзя указывать вместе с from и to":          "cannot be combined with from and to",
+	"неизвестный период: %s":                       "unknown period: %s",
+	"неподдерживаемый интервал":                    "unsupported interval",
+	"неподдерживаемая группировка: %s":             "unsupported granularity: %s",
+	"значение должно быть от 0 до %d":              "value must be between 0 and %d",
+	"дата окончания раньше даты начала":            "end date is before start date",
+	"неверное cron выражение: %v":                  "invalid cron expression: %v",
+	"не указаны получатели отчёта":                 "no report recipients specified",
+	"ожидается адрес http или https":               "expected an http or https URL",
+	"адрес во внутренней сети запрещён":            "internal network addresses are not allowed",
+	"Отчёт: %s": "Report: %s",
+	"Отчёт «%s» по расписанию %s.\nID запуска: %s": "Report \"%s\" on schedule %s.\nRun ID: %s",
+
+	// Ответы об успешных операциях
+	"Создано":  "Created",
+	"успешно":  "OK",
+	"удалено":  "Deleted",
+	"отменено": "Cancelled",
+}
@@ -0,0 +1,168 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Lang — код языка ответа
+type Lang string
+
+const (
+	RU Lang = "ru"
+	EN Lang = "en"
+
+	// Default — язык, на котором написаны исходные сообщения
+	Default = RU
+)
+
+// Тексты в коде пишутся по-русски и служат ключами каталогов.
+// Если перевода нет, клиент получает исходный текст
+var catalogs = map[Lang]map[string]string{
+	EN: en,
+}
+
+type ctxKey struct{}
+
+// Supported сообщает, есть ли перевод на язык
+func Supported(lang Lang) bool {
+	_, ok := catalogs[lang]
+	return ok || lang == Default
+}
+
+// Parse приводит тег вида en-US к поддерживаемому языку
+func Parse(tag string) (Lang, bool) {
+	base, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	lang := Lang(strings.ToLower(base))
+	if !Supported(lang) {
+		return "", false
+	}
+
+	return lang, true
+}
+
+// Negotiate выбирает язык из заголовка Accept-Language с учётом весов q.
+// Регион отбрасывается: en-GB — это en. Звёздочка означает любой язык, кроме
+// перечисленных с q=0, и даёт язык по умолчанию
+func Negotiate(header string) (Lang, bool) {
+	type candidate struct {
+		lang Lang
+		q    float64
+	}
+
+	var candidates []candidate
+	excluded := make(map[Lang]bool)
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q, ok := quality(params)
+		if !ok {
+			continue
+		}
+
+		var lang Lang
+		if strings.TrimSpace(tag) != "*" {
+			if lang, ok = Parse(tag); !ok {
+				continue
+			}
+		}
+		if q <= 0 {
+			excluded[lang] = true
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	for _, c := range candidates {
+		if c.lang != "" {
+			return c.lang, true
+		}
+		for _, lang := range []Lang{Default, EN} {
+			if !excluded[lang] {
+				return lang, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// quality возвращает вес q из параметров тега; без q вес равен 1
+func quality(params string) (float64, bool) {
+	for _, param := range strings.Split(params, ";") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(param), "q=")
+		if !ok {
+			continue
+		}
+		q, err := strconv.ParseFloat(value, 64)
+		if err != nil || q > 1 {
+			return 0, false
+		}
+		return q, true
+	}
+
+	return 1, true
+}
+
+func WithLang(ctx context.Context, lang Lang) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+func FromContext(ctx context.Context) Lang {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	if lang, ok := ctx.Value(ctxKey{}).(Lang); ok {
+		return lang
+	}
+
+	return Default
+}
+
+// Translate переводит сообщение и подставляет аргументы
+func Translate(lang Lang, msgid string, args ...any) string {
+	if translated, ok := catalogs[lang][msgid]; ok {
+		msgid = translated
+	}
+	if len(args) == 0 {
+		return msgid
+	}
+
+	return fmt.Sprintf(msgid, args...)
+}
+
+// T переводит сообщение на язык запроса
+func T(ctx context.Context, msgid string, args ...any) string {
+	return Translate(FromContext(ctx), msgid, args...)
+}
+
+// Middleware выбирает язык ответа: сначала настройка пользователя,
+// затем Accept-Language, иначе язык по умолчанию
+func Middleware(preferred func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang, ok := Lang(""), false
+		if preferred != nil {
+			lang, ok = Parse(preferred(c))
+		}
+		if !ok {
+			lang, ok = Negotiate(c.GetHeader("Accept-Language"))
+		}
+		if !ok {
+			lang = Default
+		}
+
+		c.Request = c.Request.WithContext(WithLang(c.Request.Context(), lang))
+		c.Header("Content-Language", string(lang))
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
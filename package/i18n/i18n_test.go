@@ -0,0 +1,44 @@
+package i18n
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Lang
+		ok     bool
+	}{
+		{"", "", false},
+		{"en", EN, true},
+		{"ru", RU, true},
+		{"EN", EN, true},
+		{"en-GB", EN, true},
+		{"en-US,en;q=0.9", EN, true},
+		{"ru-RU, ru;q=0.9, en-US;q=0.8, en;q=0.7", RU, true},
+		{"ru;q=0.5, en;q=0.8", EN, true},
+		{"en;q=0.8, ru;q=0.8", EN, true},
+		{" en ; q=0.3 , ru ; q=0.2 ", EN, true},
+		{"fr, de;q=0.9, en;q=0.1", EN, true},
+		{"fr, de", "", false},
+		{"en;q=0, ru;q=0.1", RU, true},
+		{"en;q=0", "", false},
+		{"en;q=abc, ru;q=0.5", RU, true},
+		{"en;q=2, ru;q=0.5", RU, true},
+		{"en;level=1;q=0.2, ru;q=0.5", RU, true},
+		{"*", Default, true},
+		{"fr, *;q=0.5", Default, true},
+		{"en;q=0.5, *", Default, true},
+		{"ru;q=0, *", EN, true},
+		{"ru;q=0, en;q=0, *", "", false},
+		{"*;q=0", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := Negotiate(tt.header)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Negotiate(%q) = %q, %v; ожидалось %q, %v", tt.header, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -1,58 +1,137 @@
 package logs
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/gin-gonic/gin"
 )
 
-const (
-	LogMessageTypeSucsess = "Успешно"
-	LogMessageTypeError   = "--> Ошибка"
+type ctxKey struct{}
 
-	LogMessageNoErrror = "успешно"
+var (
+	mu     sync.RWMutex
+	logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
+	output io.Closer
 )
 
-type LogMessage struct {
-	Title       string
-	Message     string
-	Timestamp   time.Time
-	Location    string
-	MessageType string
+// Options — параметры логгера, файл с пустым File не ведётся
+type Options struct {
+	Level      string
+	Format     string
+	File       string
+	MaxSizeMB  int
+	MaxAge     time.Duration
+	MaxBackups int
 }
 
-func NewLog(title, location string, logEror error) error {
-	errorText := ""
-	messageType := ""
-
-	if logEror != nil {
-		errorText = logEror.Error()
-		messageType = LogMessageTypeError
-	} else {
-		errorText = LogMessageNoErrror
-		messageType = LogMessageTypeSucsess
+// Init настраивает общий логгер: уровень, формат и единый writer с ротацией
+func Init(cfg Options) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		return fmt.Errorf("неизвестный уровень логирования %q: %w", cfg.Level, err)
 	}
 
-	log := &LogMessage{
-		MessageType: messageType,
-		Message:     errorText,
-		Title:       title,
-		Location:    location,
-		Timestamp:   time.Now(),
+	var (
+		writer io.Writer = os.Stdout
+		closer io.Closer
+	)
+	if cfg.File != "" {
+		rw, err := newRotatingWriter(cfg.File, int64(cfg.MaxSizeMB)<<20, cfg.MaxAge, cfg.MaxBackups)
+		if err != nil {
+			return err
+		}
+		writer = io.MultiWriter(os.Stdout, rw)
+		closer = rw
 	}
 
-	f, err := os.OpenFile("./logs.txt", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
+	opts := &slog.HandlerOptions{Level: level}
+
+	var handler slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "json":
+		handler = slog.NewJSONHandler(writer, opts)
+	case "text", "":
+		handler = slog.NewTextHandler(writer, opts)
+	default:
+		if closer != nil {
+			closer.Close()
+		}
+		return fmt.Errorf("неизвестный формат логов: %s", cfg.Format)
 	}
 
-	defer f.Close()
+	mu.Lock()
+	defer mu.Unlock()
 
-	logLine := fmt.Sprintf("[%s] - [%s]: %s - '%s' :: %s\n",
-		log.Timestamp.Format(time.RFC3339), log.MessageType, log.Title, log.Message, log.Location)
-	if _, err := f.WriteString(logLine); err != nil {
-		return err
+	if output != nil {
+		output.Close()
 	}
+	output = closer
+	logger = slog.New(traceHandler{handler})
+	slog.SetDefault(logger)
 
 	return nil
 }
+
+// Close закрывает файл логов
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if output == nil {
+		return nil
+	}
+	err := output.Close()
+	output = nil
+
+	return err
+}
+
+func Logger() *slog.Logger {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return logger
+}
+
+// WithAttrs добавляет поля ко всем записям, сделанным через FromContext(ctx)
+func WithAttrs(ctx context.Context, args ...any) context.Context {
+	return context.WithValue(ctx, ctxKey{}, FromContext(ctx).With(args...))
+}
+
+// FromContext возвращает логгер с полями текущего запроса
+func FromContext(ctx context.Context) *slog.Logger {
+	ctx = requestContext(ctx)
+	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
+		return l
+	}
+
+	return Logger()
+}
+
+// requestContext достаёт контекст запроса из gin.Context, в котором лежат поля и спан
+func requestContext(ctx context.Context) context.Context {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		return c.Request.Context()
+	}
+
+	return ctx
+}
+
+// NewLog пишет запись об операции: с ошибкой — уровень error, без — info
+func NewLog(ctx context.Context, title, location string, logErr error) {
+	ctx = requestContext(ctx)
+	l := FromContext(ctx)
+	if logErr != nil {
+		l.ErrorContext(ctx, title, "location", location, "error", logErr.Error())
+		return
+	}
+
+	l.InfoContext(ctx, title, "location", location)
+}
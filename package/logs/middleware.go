@@ -0,0 +1,56 @@
+package logs
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const userIDKey = "logs.userId"
+
+// Middleware пишет одну запись на каждый HTTP запрос вместо логгера gin.Default()
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		c.Request = c.Request.WithContext(WithAttrs(c.Request.Context(), "method", c.Request.Method, "route", route))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if userID, ok := c.Get(userIDKey); ok {
+			attrs = append(attrs, slog.Any("user_id", userID))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("errors", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		ctx := c.Request.Context()
+		FromContext(ctx).LogAttrs(ctx, level, "HTTP запрос", attrs...)
+	}
+}
+
+// SetUserID добавляет ID пользователя в запись о запросе
+func SetUserID(c *gin.Context, id int) {
+	c.Set(userIDKey, id)
+}
@@ -0,0 +1,206 @@
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotateTimeFormat — время ротации в имени старого файла. Дробная часть фиксированной длины,
+// чтобы две ротации в одну секунду не перезаписали друг друга, а сортировка по имени оставалась по времени
+const (
+	rotateTimeFormat = "20060102T150405.000000000"
+	// legacyRotateTimeFormat — имена файлов, ротированных до появления дробной части
+	legacyRotateTimeFormat = "20060102T150405"
+)
+
+// rotatingWriter пишет в файл и переименовывает его при превышении размера или возраста
+type rotatingWriter struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newRotatingWriter(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*rotatingWriter, error) {
+	w := &rotatingWriter{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+func (w *rotatingWriter) open() error {
+	if dir := filepath.Dir(w.path); dir != "." {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return fmt.Errorf("ошибка при создании директории логов: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("ошибка при открытии файла логов: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("ошибка при чтении файла логов: %w", err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = time.Now()
+
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if w.needsRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+
+	return n, err
+}
+
+func (w *rotatingWriter) needsRotate(next int) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.maxSize > 0 && w.size+int64(next) > w.maxSize {
+		return true
+	}
+
+	return w.maxAge > 0 && time.Since(w.openedAt) > w.maxAge
+}
+
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("ошибка при закрытии файла логов: %w", err)
+	}
+	w.file = nil
+
+	if err := os.Rename(w.path, w.backupName(time.Now())); err != nil {
+		// Продолжаем писать в тот же файл, иначе после неудачной ротации логи потеряются
+		if openErr := w.open(); openErr != nil {
+			return errors.Join(fmt.Errorf("ошибка при ротации файла логов: %w", err), openErr)
+		}
+		return fmt.Errorf("ошибка при ротации файла логов: %w", err)
+	}
+
+	if err := w.open(); err != nil {
+		return err
+	}
+
+	w.removeOldBackups()
+
+	return nil
+}
+
+// backupName подбирает имя старого файла по времени ротации. На системах с грубыми часами
+// время может совпасть с прошлой ротацией, тогда оно сдвигается на наносекунду
+func (w *rotatingWriter) backupName(now time.Time) string {
+	ext := filepath.Ext(w.path)
+	for {
+		backup := fmt.Sprintf("%s-%s%s", strings.TrimSuffix(w.path, ext), now.Format(rotateTimeFormat), ext)
+		if _, err := os.Lstat(backup); errors.Is(err, fs.ErrNotExist) {
+			return backup
+		}
+		now = now.Add(time.Nanosecond)
+	}
+}
+
+func (w *rotatingWriter) removeOldBackups() {
+	if w.maxBackups <= 0 {
+		return
+	}
+
+	ext := filepath.Ext(w.path)
+	backups, err := filepath.Glob(strings.TrimSuffix(w.path, ext) + "-*" + ext)
+	if err != nil || len(backups) <= w.maxBackups {
+		return
+	}
+
+	// Имена содержат время ротации, поэтому сортировка по имени — по возрасту
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-w.maxBackups] {
+		os.Remove(old)
+	}
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+// PurgeBackups удаляет старые файлы логов, ротированные раньше before.
+// Время ротации берётся из имени файла, текущий файл не трогается
+func PurgeBackups(path string, before time.Time) ([]string, error) {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext) + "-"
+
+	backups, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка при поиске старых логов: %w", err)
+	}
+
+	var removed []string
+	for _, backup := range backups {
+		rotatedAt, err := parseRotateTime(strings.TrimSuffix(strings.TrimPrefix(backup, prefix), ext))
+		if err != nil || !rotatedAt.Before(before) {
+			continue
+		}
+		if err := os.Remove(backup); err != nil {
+			return removed, fmt.Errorf("ошибка при удалении %s: %w", backup, err)
+		}
+		removed = append(removed, backup)
+	}
+
+	return removed, nil
+}
+
+func parseRotateTime(value string) (time.Time, error) {
+	rotatedAt, err := time.ParseInLocation(rotateTimeFormat, value, time.Local)
+	if err != nil {
+		return time.ParseInLocation(legacyRotateTimeFormat, value, time.Local)
+	}
+
+	return rotatedAt, nil
+}
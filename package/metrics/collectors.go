@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const scrapeTimeout = 2 * time.Second
+
+// poolCollector отдаёт статистику пула соединений на момент опроса
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	acquiredConns        *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	idleConns            *prometheus.Desc
+	maxConns             *prometheus.Desc
+	totalConns           *prometheus.Desc
+}
+
+func RegisterPool(pool *pgxpool.Pool) error {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+
+	return prometheus.Register(&poolCollector{
+		pool:                 pool,
+		acquireCount:         desc("acquire_total", "Количество успешных получений соединения."),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Суммарное время ожидания соединения."),
+		acquiredConns:        desc("acquired_connections", "Соединения, занятые в данный момент."),
+		canceledAcquireCount: desc("canceled_acquire_total", "Получения соединения, отменённые контекстом."),
+		constructingConns:    desc("constructing_connections", "Соединения в процессе установки."),
+		emptyAcquireCount:    desc("empty_acquire_total", "Получения соединения, которым пришлось ждать."),
+		idleConns:            desc("idle_connections", "Свободные соединения."),
+		maxConns:             desc("max_connections", "Максимальный размер пула."),
+		totalConns:           desc("total_connections", "Все соединения пула."),
+	})
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- p.acquireCount
+	ch <- p.acquireDuration
+	ch <- p.acquiredConns
+	ch <- p.canceledAcquireCount
+	ch <- p.constructingConns
+	ch <- p.emptyAcquireCount
+	ch <- p.idleConns
+	ch <- p.maxConns
+	ch <- p.totalConns
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := p.pool.Stat()
+
+	ch <- prometheus.MustNewConstMetric(p.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(p.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(p.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(p.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(p.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(p.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(p.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+}
+
+// CountFunc возвращает текущие значения метрики по меткам, например число рейсов по статусам
+type CountFunc func(ctx context.Context) (map[string]int, error)
+
+// countCollector запрашивает значения при каждом опросе, чтобы не хранить их отдельно от БД
+type countCollector struct {
+	desc  *prometheus.Desc
+	count CountFunc
+}
+
+func RegisterFlightsByStatus(count CountFunc) error {
+	return prometheus.Register(&countCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "flights"),
+			"Количество рейсов на табло по статусам.",
+			[]string{"status"}, nil,
+		),
+		count: count,
+	})
+}
+
+func (c *countCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *countCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), scrapeTimeout)
+	defer cancel()
+
+	counts, err := c.count(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for label, value := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(value), label)
+	}
+}
@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const Version = "3.0.3"
+
+// ginParam находит параметры пути gin вида :id и *path
+var ginParam = regexp.MustCompile(`[:*]([A-Za-z0-9_]+)`)
+
+// Operation описывает маршрут. Схемы запроса и ответа строятся по типам Go:
+// имена полей берутся из тегов json и form, ограничения — из тегов binding
+type Operation struct {
+	Tag         string
+	Summary     string
+	Description string
+	// Auth — маршрут требует заголовок Authorization: Bearer <JWT>
+	Auth  bool
+	Query any
+	Body  any
+	// BodyTypes — типы тела запроса, если это файл, а не JSON
+	BodyTypes []string
+	// Status — код успешного ответа, по умолчанию 200
+	Status   int
+	Response any
+	// ContentType — тип успешного ответа, если это не JSON
+	ContentType string
+}
+
+// Spec собирает описание API по мере регистрации маршрутов
+type Spec struct {
+	mu          sync.Mutex
+	title       string
+	version     string
+	errorBody   any
+	operations  map[string]map[string]Operation
+	deprecated  map[string]string
+	description string
+}
+
+func New(title, version, description string, errorBody any) *Spec {
+	return &Spec{
+		title:       title,
+		version:     version,
+		description: description,
+		errorBody:   errorBody,
+		operations:  make(map[string]map[string]Operation),
+		deprecated:  make(map[string]string),
+	}
+}
+
+// Add описывает маршрут; path записывается так же, как при регистрации в gin
+func (s *Spec) Add(method, path string, op Operation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.operations[path] == nil {
+		s.operations[path] = make(map[string]Operation)
+	}
+	s.operations[path][method] = op
+}
+
+// Deprecate помечает маршрут устаревшим и указывает, чем его заменить
+func (s *Spec) Deprecate(method, path, successor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deprecated[method+" "+path] = successor
+}
+
+// Has сообщает, описан ли маршрут
+func (s *Spec) Has(method, path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.operations[path][method]
+	return ok
+}
+
+// Routes возвращает описанные маршруты в виде "METHOD path"
+func (s *Spec) Routes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sortedRoutes()
+}
+
+func (s *Spec) sortedRoutes() []string {
+	var routes []string
+	for path, methods := range s.operations {
+		for method := range methods {
+			routes = append(routes, method+" "+path)
+		}
+	}
+	sort.Strings(routes)
+
+	return routes
+}
+
+func (s *Spec) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Document())
+}
+
+// Document строит документ OpenAPI по описанным маршрутам
+func (s *Spec) Document() *Document {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	schemas := newRegistry()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: s.title, Version: s.version, Description: s.description},
+		Paths:   make(map[string]map[string]*operationObject),
+		Components: Components{
+			Schemas: schemas.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				"bearer": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+
+	var errorResponse *Response
+	if s.errorBody != nil {
+		errorResponse = &Response{
+			Description: "Ошибка",
+			Content:     map[string]MediaType{"application/json": {Schema: schemas.schemaOf(s.errorBody)}},
+		}
+	}
+
+	// Маршруты обходятся по порядку, чтобы имена схем при совпадении типов не менялись между запусками
+	for _, route := range s.sortedRoutes() {
+		method, path, _ := strings.Cut(route, " ")
+		key := ginParam.ReplaceAllString(path, "{$1}")
+		if doc.Paths[key] == nil {
+			doc.Paths[key] = make(map[string]*operationObject)
+		}
+
+		successor, deprecated := s.deprecated[route]
+		doc.Paths[key][strings.ToLower(method)] = s.operation(schemas, path, s.operations[path][method], errorResponse, deprecated, successor)
+	}
+
+	return doc
+}
+
+func (s *Spec) operation(schemas *registry, path string, op Operation, errorResponse *Response, deprecated bool, successor string) *operationObject {
+	obj := &operationObject{
+		Summary:     op.Summary,
+		Description: op.Description,
+		Deprecated:  deprecated,
+		Responses:   make(map[string]*Response),
+	}
+	if op.Tag != "" {
+		obj.Tags = []string{op.Tag}
+	}
+	if deprecated {
+		obj.Description = strings.TrimSpace(obj.Description + "\n\nУстаревший маршрут, используйте " + successor + ".")
+	}
+	if op.Auth {
+		obj.Security = []map[string][]string{{"bearer": {}}}
+	}
+
+	for _, match := range ginParam.FindAllStringSubmatch(path, -1) {
+		obj.Parameters = append(obj.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+	if op.Query != nil {
+		obj.Parameters = append(obj.Parameters, schemas.queryParameters(op.Query)...)
+	}
+
+	if op.Body != nil {
+		obj.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: schemas.schemaOf(op.Body)}},
+		}
+	}
+	if len(op.BodyTypes) > 0 {
+		obj.RequestBody = &RequestBody{Required: true, Content: make(map[string]MediaType)}
+		for _, contentType := range op.BodyTypes {
+			obj.RequestBody.Content[contentType] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case op.ContentType != "":
+		success.Content = map[string]MediaType{op.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	case op.Response != nil:
+		success.Content = map[string]MediaType{"application/json": {Schema: schemas.schemaOf(op.Response)}}
+	}
+	obj.Responses[strconv.Itoa(status)] = success
+
+	if errorResponse != nil {
+		obj.Responses["default"] = errorResponse
+	}
+
+	return obj
+}
@@ -0,0 +1,263 @@
+package openapi
+
+import (
+	"AirPort/package/validation"
+	"encoding/json"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// registry выносит именованные структуры в components/schemas,
+// чтобы одна и та же модель описывалась в документе один раз
+type registry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newRegistry() *registry {
+	return &registry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (r *registry) schemaOf(v any) *Schema {
+	return r.schema(reflect.TypeOf(v))
+}
+
+func (r *registry) schema(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Pointer {
+		t, nullable = t.Elem(), true
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: nullable}
+	case t == rawType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string", Nullable: nullable}
+	case reflect.Bool:
+		return &Schema{Type: "boolean", Nullable: nullable}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Nullable: nullable}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Nullable: nullable}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + r.register(t)}
+	}
+
+	return &Schema{}
+}
+
+func (r *registry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := r.schemas[name]; taken {
+		name = path.Base(t.PkgPath()) + "." + name
+	}
+
+	// Имя занимается до обхода полей, чтобы рекурсивные типы ссылались сами на себя
+	r.names[t] = name
+	r.schemas[name] = &Schema{}
+	*r.schemas[name] = *r.object(t)
+
+	return name
+}
+
+func (r *registry) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	r.fields(t, func(name string, field reflect.StructField) {
+		prop := r.schema(field.Type)
+		required := applyBinding(prop, field.Tag.Get("binding"))
+		obj.Properties[name] = prop
+		if required {
+			obj.Required = append(obj.Required, name)
+		}
+	})
+
+	return obj
+}
+
+// queryParameters описывает параметры строки запроса по тегам form
+func (r *registry) queryParameters(v any) []Parameter {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema := r.schema(field.Type)
+		required := applyBinding(schema, field.Tag.Get("binding"))
+		params = append(params, Parameter{Name: name, In: "query", Required: required, Schema: schema})
+	}
+
+	return params
+}
+
+// fields обходит поля в том же порядке и с теми же именами, что и encoding/json
+func (r *registry) fields(t reflect.Type, visit func(name string, field reflect.StructField)) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				r.fields(embedded, visit)
+				continue
+			}
+		}
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		visit(name, field)
+	}
+}
+
+// applyBinding переносит ограничения из тега binding в схему и сообщает,
+// обязательно ли поле. Правила после dive относятся к элементам массива
+func applyBinding(schema *Schema, tag string) bool {
+	required := false
+	target := schema
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "required" && target == schema {
+			required = true
+		}
+		// Рядом со ссылкой $ref в OpenAPI 3.0 другие ключи игнорируются
+		if target.Ref != "" {
+			continue
+		}
+
+		switch name {
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "oneof":
+			target.Enum = strings.Fields(param)
+		case "min", "gte":
+			setMin(target, param, 0)
+		case "max", "lte":
+			setMax(target, param)
+		case "gt":
+			setMin(target, param, 1)
+		case "len":
+			setMin(target, param, 0)
+			setMax(target, param)
+		case "datetime":
+			if param == time.DateOnly {
+				target.Format = "date"
+			} else {
+				target.Description = describe(target.Description, "формат "+param)
+			}
+		case "future":
+			target.Description = describe(target.Description, "дата в будущем")
+		case "password":
+			minLength, maxLength := validation.PasswordMinLength, validation.PasswordMaxLength
+			target.MinLength, target.MaxLength = &minLength, &maxLength
+			target.Description = describe(target.Description, "буквы и цифры")
+		default:
+			if pattern, ok := validation.Patterns[name]; ok {
+				target.Pattern = pattern
+			}
+		}
+	}
+
+	return required
+}
+
+// setMin задаёт нижнюю границу: длину для строк, число элементов для массивов
+// и значение для чисел. offset превращает строгое gt в нестрогий minimum
+func setMin(schema *Schema, param string, offset float64) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		length := int(n + offset)
+		schema.MinLength = &length
+	case "array":
+		items := int(n + offset)
+		schema.MinItems = &items
+	default:
+		value := n + offset
+		schema.Minimum = &value
+	}
+}
+
+func setMax(schema *Schema, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "string":
+		length := int(n)
+		schema.MaxLength = &length
+	case "array":
+		items := int(n)
+		schema.MaxItems = &items
+	default:
+		schema.Maximum = &n
+	}
+}
+
+func describe(description, text string) string {
+	if description == "" {
+		return text
+	}
+
+	return description + "; " + text
+}
@@ -0,0 +1,29 @@
+package openapi
+
+import (
+	"html/template"
+	"io"
+)
+
+// swaggerUI подключает Swagger UI с CDN, чтобы не хранить статику в репозитории
+var swaggerUI = template.Must(template.New("swagger").Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+	<meta charset="utf-8">
+	<title>{{.Title}}</title>
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+	<script>
+		window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui", persistAuthorization: true});
+	</script>
+</body>
+</html>
+`))
+
+// WriteUI выводит страницу Swagger UI для документа по адресу specURL
+func WriteUI(w io.Writer, title, specURL string) error {
+	return swaggerUI.Execute(w, struct{ Title, SpecURL string }{title, specURL})
+}
@@ -0,0 +1,70 @@
+package requestid
+
+import (
+	"AirPort/package/logs"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	Header = "X-Request-ID"
+
+	maxLength = 128
+)
+
+type ctxKey struct{}
+
+// Middleware принимает X-Request-ID клиента или создаёт новый и кладёт его в контекст запроса
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(Header)
+		if !valid(id) {
+			id = New()
+		}
+
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), id))
+		c.Header(Header, id)
+
+		c.Next()
+	}
+}
+
+// NewContext возвращает контекст с ID запроса, который попадёт во все записи лога
+func NewContext(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, ctxKey{}, id)
+	return logs.WithAttrs(ctx, "request_id", id)
+}
+
+func FromContext(ctx context.Context) string {
+	if c, ok := ctx.(*gin.Context); ok && c.Request != nil {
+		ctx = c.Request.Context()
+	}
+	id, _ := ctx.Value(ctxKey{}).(string)
+
+	return id
+}
+
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		if r < 0x21 || r > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// certCheckInterval ограничивает частоту проверки файлов сертификата при рукопожатиях
+const certCheckInterval = 10 * time.Second
+
+// certReloader перечитывает сертификат и ключ, когда файлы меняются на диске,
+// поэтому обновление сертификата не требует перезапуска сервера
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	modTime   time.Time
+	checkedAt time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+
+	modTime, err := r.latestModTime()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < certCheckInterval {
+		return r.cert, nil
+	}
+	r.checkedAt = time.Now()
+
+	modTime, err := r.latestModTime()
+	if err != nil {
+		slog.Error("Проверка сертификата TLS", "error", err)
+		return r.cert, nil
+	}
+	if !modTime.After(r.modTime) {
+		return r.cert, nil
+	}
+
+	// Пока новый сертификат не загрузился целиком, сервер продолжает работать со старым
+	if err := r.load(modTime); err != nil {
+		slog.Error("Перезагрузка сертификата TLS", "error", err)
+		return r.cert, nil
+	}
+	slog.Info("Сертификат TLS перезагружен", "cert", r.certFile)
+
+	return r.cert, nil
+}
+
+func (r *certReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("ошибка загрузки сертификата TLS: %w", err)
+	}
+
+	r.cert = &cert
+	r.modTime = modTime
+	r.checkedAt = time.Now()
+
+	return nil
+}
+
+func (r *certReloader) latestModTime() (time.Time, error) {
+	var latest time.Time
+	for _, path := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("ошибка чтения файла %s: %w", path, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+
+	return latest, nil
+}
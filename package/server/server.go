@@ -1,27 +1,82 @@
 package server
 
 import (
+	"AirPort/package/health"
 	"context"
+	"crypto/tls"
+	"errors"
 	"net/http"
 	"time"
 )
 
 type Server struct {
 	httpServer *http.Server
+
+	// Health переводится в неготовое состояние перед остановкой
+	Health *health.Checker
+	// DrainDelay — время, за которое балансировщик успевает снять трафик
+	DrainDelay time.Duration
+}
+
+// Options — параметры HTTP-сервера. HTTPS включается, когда заданы оба файла TLS
+type Options struct {
+	Port              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	TLSCertFile string
+	TLSKeyFile  string
 }
 
-func (s *Server) RunServer(port string, h http.Handler) error {
+// RunServer запускает HTTP или, если заданы сертификат и ключ, HTTPS.
+// Штатная остановка через StopServer не считается ошибкой
+func (s *Server) RunServer(cfg Options, h http.Handler) error {
 	s.httpServer = &http.Server{
-		Addr:           ":" + port,
-		MaxHeaderBytes: 1 << 20,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		Handler:        h,
+		Addr:              ":" + cfg.Port,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		Handler:           h,
+	}
+
+	var err error
+	if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+		var certs *certReloader
+		certs, err = newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return err
+		}
+
+		s.httpServer.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: certs.GetCertificate,
+		}
+		err = s.httpServer.ListenAndServeTLS("", "")
+	} else {
+		err = s.httpServer.ListenAndServe()
+	}
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
 	}
 
-	return s.httpServer.ListenAndServe()
+	return err
 }
 
 func (s *Server) StopServer(c context.Context) error {
+	if s.Health != nil {
+		s.Health.SetShuttingDown()
+
+		select {
+		case <-time.After(s.DrainDelay):
+		case <-c.Done():
+		}
+	}
+
 	return s.httpServer.Shutdown(c)
 }
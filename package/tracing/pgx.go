@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanNames — сообщения pgx, которые пишутся после завершения запроса вместе с его длительностью
+var spanNames = map[string]string{
+	"Query":     "pgx.query",
+	"Exec":      "pgx.exec",
+	"SendBatch": "pgx.batch",
+	"CopyFrom":  "pgx.copy_from",
+}
+
+// QueryLogger создаёт спан на каждый SQL запрос.
+// pgx v4 не даёт хуков до начала запроса, поэтому спан строится задним числом по его длительности.
+func QueryLogger() pgx.Logger {
+	return pgx.LoggerFunc(func(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+		name, ok := spanNames[msg]
+		if !ok {
+			return
+		}
+		duration, ok := data["time"].(time.Duration)
+		if !ok {
+			return
+		}
+
+		end := time.Now()
+		_, span := otel.Tracer(tracerName).Start(ctx, name,
+			trace.WithTimestamp(end.Add(-duration)),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL),
+		)
+		if sql, ok := data["sql"].(string); ok {
+			span.SetAttributes(semconv.DBQueryText(sql))
+		}
+		if err, ok := data["err"].(error); ok {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+	})
+}
@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"AirPort/package/apperrors"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// DateTimeLayout — формат даты и времени рейсов в запросах
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+const (
+	PasswordMinLength = 8
+	// bcrypt учитывает только первые 72 байта пароля
+	PasswordMaxLength = 72
+)
+
+// Patterns — выражения пользовательских тегов. Они записаны без флагов Go,
+// чтобы те же строки годились для pattern в описании OpenAPI
+var Patterns = map[string]string{
+	"flightnumber": `^([A-Za-z]{2}|[A-Za-z][0-9]|[0-9][A-Za-z])[0-9]{1,4}$`,
+	"airline":      `^[A-Za-z0-9]{2}$`,
+	"iata":         `^[A-Za-z]{3}$`,
+	"icao":         `^[A-Za-z0-9]{4}$`,
+	"airlineicao":  `^[A-Za-z]{3}$`,
+}
+
+var messages = map[string]string{
+	"required":     "обязательное поле",
+	"email":        "неверный формат email",
+	"url":          "неверный адрес",
+	"oneof":        "допустимые значения: %s",
+	"min":          "значение должно быть не меньше %s",
+	"max":          "значение должно быть не больше %s",
+	"min.string":   "длина должна быть не меньше %s символов",
+	"max.string":   "длина должна быть не больше %s символов",
+	"min.slice":    "нужно не меньше %s элементов",
+	"max.slice":    "нужно не больше %s элементов",
+	"gt":           "значение должно быть больше %s",
+	"gte":          "значение должно быть не меньше %s",
+	"len":          "длина должна быть %s",
+	"datetime":     "неверный формат, ожидается %s",
+	"flightnumber": "неверный номер рейса, ожидается код авиакомпании и до 4 цифр, например SU1234",
+	"airline":      "неверный код авиакомпании IATA, ожидается 2 символа",
+	"iata":         "неверный код аэропорта IATA, ожидается 3 буквы",
+	"icao":         "неверный код аэропорта ICAO, ожидается 4 символа",
+	"airlineicao":  "неверный код авиакомпании ICAO, ожидается 3 буквы",
+	"password":     "пароль должен быть от %d до %d символов и содержать буквы и цифры",
+	"future":       "дата должна быть в будущем",
+}
+
+// Валидаторы регистрируются в движке gin, поэтому теги binding работают
+// и в ShouldBindJSON, и в Struct
+func init() {
+	engine := binding.Validator.Engine().(*validator.Validate)
+
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		}
+		return name
+	})
+
+	for tag, pattern := range Patterns {
+		engine.RegisterValidation(tag, matches(regexp.MustCompile(pattern)))
+	}
+	engine.RegisterValidation("password", validatePassword)
+	engine.RegisterValidation("future", validateFuture)
+}
+
+// Struct проверяет структуру по тегам binding вне HTTP запроса
+func Struct(v any) error {
+	if err := binding.Validator.ValidateStruct(v); err != nil {
+		return FromError(err)
+	}
+
+	return nil
+}
+
+// FromError превращает ошибку разбора или проверки запроса в ошибку с полями:
+// 422, если данные не прошли проверку, и 400, если тело не удалось разобрать
+func FromError(err error) *apperrors.Error {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperrors.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			format, args := message(fe)
+			fields = append(fields, apperrors.NewFieldError(fieldPath(fe), format, args...))
+		}
+		return apperrors.Invalid(fields).Wrap(err)
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return apperrors.BadRequest("некорректный JSON").WithFields(
+			apperrors.NewFieldError(typeErr.Field, "ожидается тип %s", typeErr.Type.String()),
+		).Wrap(err)
+	}
+
+	return apperrors.BadRequest("некорректный JSON").Wrap(err)
+}
+
+// fieldPath убирает имя корневой структуры: RequestData.board.departure -> board.departure
+func fieldPath(fe validator.FieldError) string {
+	_, path, found := strings.Cut(fe.Namespace(), ".")
+	if !found {
+		return fe.Field()
+	}
+
+	return path
+}
+
+// message возвращает шаблон сообщения и его аргументы, перевод выполняется при ответе
+func message(fe validator.FieldError) (string, []any) {
+	template, ok := messages[fe.Tag()+"."+fe.Kind().String()]
+	if !ok {
+		template, ok = messages[fe.Tag()]
+	}
+
+	switch {
+	case !ok:
+		return "недопустимое значение", nil
+	case fe.Tag() == "password":
+		return template, []any{PasswordMinLength, PasswordMaxLength}
+	case strings.Contains(template, "%s"):
+		return template, []any{fe.Param()}
+	}
+
+	return template, nil
+}
+
+func matches(re *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return re.MatchString(fl.Field().String())
+	}
+}
+
+func validatePassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len(password) < PasswordMinLength || len(password) > PasswordMaxLength {
+		return false
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+
+	return hasLetter && hasDigit
+}
+
+// validateFuture принимает time.Time или строку в формате DateTimeLayout
+func validateFuture(fl validator.FieldLevel) bool {
+	switch value := fl.Field().Interface().(type) {
+	case time.Time:
+		return value.After(time.Now())
+	case string:
+		t, err := time.ParseInLocation(DateTimeLayout, value, time.Local)
+		return err == nil && t.After(time.Now())
+	}
+
+	return false
+}
@@ -0,0 +1,126 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+type tagged struct {
+	FlightNumber string `json:"flightNumber" binding:"omitempty,flightnumber"`
+	Airline      string `json:"airline" binding:"omitempty,airline"`
+	Iata         string `json:"iata" binding:"omitempty,iata"`
+	Icao         string `json:"icao" binding:"omitempty,icao"`
+	AirlineIcao  string `json:"airlineIcao" binding:"omitempty,airlineicao"`
+	Password     string `json:"password" binding:"omitempty,password"`
+	Departure    string `json:"departure" binding:"omitempty,future"`
+}
+
+func TestTags(t *testing.T) {
+	future := time.Now().Add(time.Hour).Format(DateTimeLayout)
+	past := time.Now().Add(-time.Hour).Format(DateTimeLayout)
+
+	tests := []struct {
+		name  string
+		value tagged
+		field string
+	}{
+		{"номер рейса с кодом из букв", tagged{FlightNumber: "SU1234"}, ""},
+		{"номер рейса с цифрой в коде", tagged{FlightNumber: "U6101"}, ""},
+		{"номер рейса с цифрой в начале кода", tagged{FlightNumber: "7R1"}, ""},
+		{"номер рейса из строчных букв", tagged{FlightNumber: "su1"}, ""},
+		{"номер рейса без цифр", tagged{FlightNumber: "SU"}, "flightNumber"},
+		{"номер рейса из пяти цифр", tagged{FlightNumber: "SU12345"}, "flightNumber"},
+		{"номер рейса с кодом из двух цифр", tagged{FlightNumber: "121234"}, "flightNumber"},
+		{"код авиакомпании IATA", tagged{Airline: "S7"}, ""},
+		{"код авиакомпании IATA из трёх символов", tagged{Airline: "AFL"}, "airline"},
+		{"код аэропорта IATA", tagged{Iata: "SVO"}, ""},
+		{"код аэропорта IATA с цифрой", tagged{Iata: "SV0"}, "iata"},
+		{"код аэропорта IATA из четырёх букв", tagged{Iata: "UUEE"}, "iata"},
+		{"код аэропорта ICAO", tagged{Icao: "UUEE"}, ""},
+		{"код площадки ICAO с цифрами", tagged{Icao: "00AK"}, ""},
+		{"код аэропорта ICAO из трёх букв", tagged{Icao: "UUE"}, "icao"},
+		{"код авиакомпании ICAO", tagged{AirlineIcao: "AFL"}, ""},
+		{"код авиакомпании ICAO с цифрой", tagged{AirlineIcao: "AF1"}, "airlineIcao"},
+		{"пароль из букв и цифр", tagged{Password: "secret123"}, ""},
+		{"пароль кириллицей", tagged{Password: "пароль123"}, ""},
+		{"короткий пароль", tagged{Password: "abc123"}, "password"},
+		{"пароль без цифр", tagged{Password: "password"}, "password"},
+		{"пароль без букв", tagged{Password: "12345678"}, "password"},
+		{"пароль длиннее 72 байт", tagged{Password: strings.Repeat("a1", 37)}, "password"},
+		{"вылет в будущем", tagged{Departure: future}, ""},
+		{"вылет в прошлом", tagged{Departure: past}, "departure"},
+		{"вылет в другом формате", tagged{Departure: "2099-01-01T10:00:00"}, "departure"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Struct(tt.value)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				return
+			}
+
+			appErr := FromError(err)
+			if len(appErr.Fields) != 1 || appErr.Fields[0].Field != tt.field {
+				t.Fatalf("ожидалась ошибка поля %s, получено %+v", tt.field, appErr.Fields)
+			}
+			if appErr.Fields[0].Message == "" || appErr.Fields[0].Message == "недопустимое значение" {
+				t.Errorf("у тега поля %s нет своего сообщения", tt.field)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	type nested struct {
+		Board struct {
+			Departure string `json:"departure" binding:"required"`
+		} `json:"board"`
+	}
+
+	var typed struct {
+		SeatCapacity int `json:"seatCapacity"`
+	}
+	typeErr := json.Unmarshal([]byte(`{"seatCapacity": "много"}`), &typed)
+	syntaxErr := json.Unmarshal([]byte(`{"seatCapacity": `), &typed)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		field  string
+	}{
+		{"ошибка проверки", binding.Validator.ValidateStruct(nested{}), http.StatusUnprocessableEntity, "board.departure"},
+		{"неверный тип поля", typeErr, http.StatusBadRequest, "seatCapacity"},
+		{"неразбираемый JSON", syntaxErr, http.StatusBadRequest, ""},
+		{"произвольная ошибка", errors.New("EOF"), http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr := FromError(tt.err)
+			if appErr.Status() != tt.status {
+				t.Errorf("статус %d, ожидался %d", appErr.Status(), tt.status)
+			}
+
+			var field string
+			if len(appErr.Fields) > 0 {
+				field = appErr.Fields[0].Field
+			}
+			if field != tt.field {
+				t.Errorf("поле %q, ожидалось %q", field, tt.field)
+			}
+			if appErr.Err == nil {
+				t.Errorf("исходная ошибка потеряна")
+			}
+		})
+	}
+}